@@ -0,0 +1,192 @@
+// Command genrunways generates the runway dataset embedded in the airports
+// package from an OurAirports-style runways.csv file. Only runways at airports
+// in the airports dataset are included, and closed runways are skipped.
+//
+// Usage:
+//
+//	go run ./airports/internal/genrunways [-o runways.json] [runways.csv | URL]
+//
+// The airports package runs it with go generate, using the runways.csv file
+// checked in next to it, so the dataset can be regenerated offline. The file
+// can also be read directly from an HTTP(S) URL, such as the one published
+// by OurAirports.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.elara.ws/taf/airports"
+)
+
+func main() {
+	out := flag.String("o", "runways.json", "Path to the generated file")
+	flag.Parse()
+
+	if flag.NArg() > 1 {
+		fmt.Fprintln(os.Stderr, "Usage: genrunways [-o runways.json] [runways.csv | URL]")
+		os.Exit(2)
+	}
+
+	src := "runways.csv"
+	if flag.NArg() == 1 {
+		src = flag.Arg(0)
+	}
+
+	fl, err := open(src)
+	if err != nil {
+		fatal(err)
+	}
+	defer fl.Close()
+
+	rwys, err := parseRunways(fl, func(icao string) bool {
+		_, ok := airports.Airports[icao]
+		return ok
+	})
+	if err != nil {
+		fatal(err)
+	}
+
+	data, err := json.Marshal(rwys)
+	if err != nil {
+		fatal(err)
+	}
+
+	err = os.WriteFile(*out, append(data, '\n'), 0o644)
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// open opens the runways.csv file at the given path or HTTP(S) URL
+func open(src string) (io.ReadCloser, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return os.Open(src)
+	}
+
+	res, err := http.Get(src)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("unexpected status: %s", res.Status)
+	}
+
+	return res.Body, nil
+}
+
+// parseRunways reads the runway ends from an OurAirports runways.csv file,
+// keyed by airport identifier. Only airports for which include returns
+// true are included.
+func parseRunways(r io.Reader, include func(icao string) bool) (map[string][]airports.Runway, error) {
+	cr := csv.NewReader(r)
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	cols := map[string]int{}
+	for i, name := range header {
+		cols[name] = i
+	}
+
+	for _, name := range []string{"airport_ident", "le_ident", "he_ident"} {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	out := map[string][]airports.Runway{}
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		field := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(rec) {
+				return strings.TrimSpace(rec[i])
+			}
+			return ""
+		}
+
+		icao := field("airport_ident")
+		if !include(icao) || field("closed") == "1" {
+			continue
+		}
+
+		base := airports.Runway{
+			Length:  atoi(field("length_ft")),
+			Width:   atoi(field("width_ft")),
+			Surface: field("surface"),
+			Lighted: field("lighted") == "1",
+		}
+
+		le, he := base, base
+		le.Ident, he.Ident = field("le_ident"), field("he_ident")
+		le.Heading, he.Heading = atof(field("le_heading_degT")), atof(field("he_heading_degT"))
+
+		// If the headings aren't known, calculate them from
+		// the coordinates of the runway ends when possible.
+		if le.Heading == 0 && he.Heading == 0 {
+			lat1, lon1 := atof(field("le_latitude_deg")), atof(field("le_longitude_deg"))
+			lat2, lon2 := atof(field("he_latitude_deg")), atof(field("he_longitude_deg"))
+			if (lat1 != 0 || lon1 != 0) && (lat2 != 0 || lon2 != 0) {
+				le.Heading = bearing(lat1, lon1, lat2, lon2)
+				he.Heading = math.Mod(le.Heading+180, 360)
+			}
+		}
+
+		for _, end := range []airports.Runway{le, he} {
+			if end.Ident != "" {
+				out[icao] = append(out[icao], end)
+			}
+		}
+	}
+
+	return out, nil
+}
+
+// bearing returns the initial true bearing in degrees from
+// the first point to the second, rounded to one decimal place.
+func bearing(lat1, lon1, lat2, lon2 float64) float64 {
+	lat1, lat2 = lat1*math.Pi/180, lat2*math.Pi/180
+	dLon := (lon2 - lon1) * math.Pi / 180
+
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+
+	deg := math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+	return math.Round(deg*10) / 10
+}
+
+// atoi parses an integer, returning zero if it's invalid
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// atof parses a float, returning zero if it's invalid
+func atof(s string) float64 {
+	f, _ := strconv.ParseFloat(s, 64)
+	return f
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "genrunways:", err)
+	os.Exit(1)
+}
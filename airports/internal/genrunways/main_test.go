@@ -0,0 +1,37 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+	"go.elara.ws/taf/airports"
+)
+
+func TestParseRunways(t *testing.T) {
+	const data = `"id","airport_ref","airport_ident","length_ft","width_ft","surface","lighted","closed","le_ident","le_latitude_deg","le_longitude_deg","le_elevation_ft","le_heading_degT","le_displaced_threshold_ft","he_ident","he_latitude_deg","he_longitude_deg","he_elevation_ft","he_heading_degT","he_displaced_threshold_ft"
+1,2,"EGLL",12799,164,"ASP",1,0,"09L",51.4775,-0.484913,79,89.6,1013,"27R",51.4777,-0.433446,78,269.6,
+2,2,"EGLL",12008,164,"ASP",1,0,"09R",51.4647,-0.482325,75,,1004,"27L",51.4650,-0.434056,77,,
+3,2,"EGLL",6000,150,"ASP",0,1,"05",,,,,,"23",,,,,
+4,3,"XXXX",1000,50,"GRS",0,0,"18",,,,,,"36",,,,,`
+
+	rwys, err := parseRunways(strings.NewReader(data), func(icao string) bool {
+		return icao == "EGLL"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]airports.Runway{
+		"EGLL": {
+			{Ident: "09L", Heading: 89.6, Length: 12799, Width: 164, Surface: "ASP", Lighted: true},
+			{Ident: "27R", Heading: 269.6, Length: 12799, Width: 164, Surface: "ASP", Lighted: true},
+			{Ident: "09R", Heading: 89.4, Length: 12008, Width: 164, Surface: "ASP", Lighted: true},
+			{Ident: "27L", Heading: 269.4, Length: 12008, Width: 164, Surface: "ASP", Lighted: true},
+		},
+	}
+
+	if diff := deep.Equal(rwys, expected); diff != nil {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,360 @@
+package airports
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var (
+	indexOnce sync.Once
+	iataIndex map[string]string
+	geoIndex  *kdTree
+)
+
+// buildIndexes builds the IATA and spatial indexes from Airports
+func buildIndexes() {
+	iataIndex = make(map[string]string, len(Airports))
+	list := make([]Airport, 0, len(Airports))
+	for icao, a := range Airports {
+		if a.IATA != "" {
+			// Prefer the airport whose ICAO code sorts first if there
+			// are duplicates, so that lookups are deterministic.
+			if prev, ok := iataIndex[a.IATA]; !ok || icao < prev {
+				iataIndex[a.IATA] = icao
+			}
+		}
+		list = append(list, a)
+	}
+	geoIndex = newKDTree(list)
+}
+
+// ByIATA returns the airport with the given IATA code.
+// The code is case-insensitive.
+func ByIATA(code string) (Airport, bool) {
+	indexOnce.Do(buildIndexes)
+	icao, ok := iataIndex[strings.ToUpper(code)]
+	if !ok {
+		return Airport{}, false
+	}
+	return Airports[icao], true
+}
+
+// Nearest returns the n airports closest to the given coordinates, in
+// order of increasing distance.
+func Nearest(lat, lon float64, n int) []Airport {
+	indexOnce.Do(buildIndexes)
+	return geoIndex.nearest(lat, lon, n)
+}
+
+// Distance returns the great-circle distance in kilometers between two points.
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	// The chord length between the points on a unit sphere
+	p1, p2 := toVector(lat1, lon1), toVector(lat2, lon2)
+	chord := math.Sqrt(sqDist(p1, p2))
+	return 2 * earthRadius * math.Asin(math.Min(chord/2, 1))
+}
+
+// earthRadius is the mean radius of the Earth in kilometers
+const earthRadius = 6371.0
+
+// Search returns the airports matching the given query, best matches
+// first. The query is matched against the ICAO and IATA codes as well as
+// the name and city of each airport. Words in the query match the start of
+// words in the name or city, and small typos are tolerated in longer words.
+func Search(query string) []Airport {
+	words := searchWords(query)
+	if len(words) == 0 {
+		return nil
+	}
+	code := strings.ToUpper(strings.TrimSpace(query))
+
+	type result struct {
+		airport Airport
+		score   int
+	}
+
+	var results []result
+	for _, a := range Airports {
+		if score := searchScore(a, code, words); score > 0 {
+			results = append(results, result{a, score})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return results[i].airport.ICAO < results[j].airport.ICAO
+	})
+
+	out := make([]Airport, len(results))
+	for i, r := range results {
+		out[i] = r.airport
+	}
+	return out
+}
+
+// Scores used to rank search results
+const (
+	scoreCode   = 100
+	scoreExact  = 80
+	scorePrefix = 60
+	scoreFuzzy  = 20
+)
+
+// searchScore returns how well an airport matches a query,
+// or zero if it doesn't match at all.
+func searchScore(a Airport, code string, words []string) int {
+	if code == a.ICAO || (a.IATA != "" && code == a.IATA) {
+		return scoreCode
+	}
+
+	name := searchWords(a.Name)
+	if slices.Equal(words, name) {
+		return scoreExact
+	}
+
+	candidates := append(slices.Clip(name), searchWords(a.City)...)
+
+	score := scorePrefix
+	for _, w := range words {
+		switch {
+		case matchesPrefix(w, candidates):
+		case matchesFuzzy(w, candidates):
+			score = scoreFuzzy
+		default:
+			return 0
+		}
+	}
+
+	// Prefer airports whose names are closer in length to the query
+	return score - min(len(candidates)-len(words), scoreFuzzy-1)
+}
+
+// matchesPrefix checks whether w is a prefix of any of the candidates
+func matchesPrefix(w string, candidates []string) bool {
+	for _, c := range candidates {
+		if strings.HasPrefix(c, w) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesFuzzy checks whether w is within one edit of any of the
+// candidates. Words shorter than four characters never match.
+func matchesFuzzy(w string, candidates []string) bool {
+	if len(w) < 4 {
+		return false
+	}
+	for _, c := range candidates {
+		if withinOneEdit(w, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// withinOneEdit checks whether a can be turned into b
+// by inserting, deleting, or replacing a single character.
+func withinOneEdit(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) > len(rb) {
+		ra, rb = rb, ra
+	}
+	if len(rb)-len(ra) > 1 {
+		return false
+	}
+
+	i := 0
+	for i < len(ra) && ra[i] == rb[i] {
+		i++
+	}
+
+	if i == len(rb) {
+		return true
+	}
+
+	if len(ra) == len(rb) {
+		// Replace the first differing character
+		return string(ra[i+1:]) == string(rb[i+1:])
+	}
+	// Insert the first differing character
+	return string(ra[i:]) == string(rb[i+1:])
+}
+
+// accentReplacer removes the accents from common Latin letters
+var accentReplacer = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a",
+	"ç", "c", "è", "e", "é", "e", "ê", "e", "ë", "e",
+	"ì", "i", "í", "i", "î", "i", "ï", "i", "ñ", "n",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o", "ø", "o",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u", "ý", "y", "ÿ", "y",
+	"ß", "ss",
+)
+
+// searchWords splits s into lowercase words with
+// the accents removed from common Latin letters
+func searchWords(s string) []string {
+	var b strings.Builder
+	for _, r := range accentReplacer.Replace(strings.ToLower(s)) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsNumber(r):
+			b.WriteRune(r)
+		default:
+			b.WriteByte(' ')
+		}
+	}
+	return strings.Fields(b.String())
+}
+
+// vector is a point on the unit sphere
+type vector [3]float64
+
+// toVector converts coordinates in degrees to a point on the unit sphere
+func toVector(lat, lon float64) vector {
+	lat, lon = lat*math.Pi/180, lon*math.Pi/180
+	return vector{
+		math.Cos(lat) * math.Cos(lon),
+		math.Cos(lat) * math.Sin(lon),
+		math.Sin(lat),
+	}
+}
+
+// sqDist returns the squared straight-line distance between two points
+func sqDist(a, b vector) float64 {
+	var sum float64
+	for i := range a {
+		d := a[i] - b[i]
+		sum += d * d
+	}
+	return sum
+}
+
+// kdTree is a 3-dimensional k-d tree of airports, stored as points on the
+// unit sphere. The straight-line distance between points on the sphere
+// increases with the great-circle distance, so the nearest points in the
+// tree are also the nearest airports.
+type kdTree struct {
+	nodes []kdNode
+	root  int
+}
+
+// kdNode is a node in a kdTree. Its children are indexes
+// into the tree's nodes, or -1 if there's no child.
+type kdNode struct {
+	airport     Airport
+	point       vector
+	axis        int
+	left, right int
+}
+
+// newKDTree builds a balanced k-d tree from the given airports
+func newKDTree(list []Airport) *kdTree {
+	nodes := make([]kdNode, len(list))
+	for i, a := range list {
+		nodes[i] = kdNode{airport: a, point: toVector(a.Latitude, a.Longitude)}
+	}
+
+	t := &kdTree{}
+	t.root = t.build(nodes, 0)
+	return t
+}
+
+// build adds the given nodes to the tree, splitting them at the
+// median along the given axis, and returns the index of the root.
+func (t *kdTree) build(nodes []kdNode, depth int) int {
+	if len(nodes) == 0 {
+		return -1
+	}
+
+	axis := depth % 3
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].point[axis] != nodes[j].point[axis] {
+			return nodes[i].point[axis] < nodes[j].point[axis]
+		}
+		return nodes[i].airport.ICAO < nodes[j].airport.ICAO
+	})
+
+	mid := len(nodes) / 2
+	node := nodes[mid]
+	node.axis = axis
+	node.left = t.build(nodes[:mid], depth+1)
+	node.right = t.build(nodes[mid+1:], depth+1)
+
+	t.nodes = append(t.nodes, node)
+	return len(t.nodes) - 1
+}
+
+// nearest returns the n airports nearest to the given coordinates
+func (t *kdTree) nearest(lat, lon float64, n int) []Airport {
+	if n <= 0 {
+		return nil
+	}
+
+	target := toVector(lat, lon)
+	h := &candidateHeap{}
+	t.search(t.root, target, n, h)
+
+	out := make([]Airport, h.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = t.nodes[heap.Pop(h).(candidate).node].airport
+	}
+	return out
+}
+
+// search finds the n nearest nodes in the subtree starting at i,
+// keeping the best candidates found so far in h.
+func (t *kdTree) search(i int, target vector, n int, h *candidateHeap) {
+	if i == -1 {
+		return
+	}
+
+	node := &t.nodes[i]
+	d := sqDist(node.point, target)
+	if h.Len() < n {
+		heap.Push(h, candidate{i, d})
+	} else if d < (*h)[0].dist {
+		(*h)[0] = candidate{i, d}
+		heap.Fix(h, 0)
+	}
+
+	diff := target[node.axis] - node.point[node.axis]
+	near, far := node.left, node.right
+	if diff > 0 {
+		near, far = far, near
+	}
+
+	t.search(near, target, n, h)
+	// Only search the other side if it could contain a closer node
+	if h.Len() < n || diff*diff < (*h)[0].dist {
+		t.search(far, target, n, h)
+	}
+}
+
+// candidate is a node that may be one of the nearest to the target
+type candidate struct {
+	node int
+	dist float64
+}
+
+// candidateHeap is a max-heap of candidates by distance, so that
+// the furthest candidate can be replaced when a closer one is found.
+type candidateHeap []candidate
+
+func (h candidateHeap) Len() int           { return len(h) }
+func (h candidateHeap) Less(i, j int) bool { return h[i].dist > h[j].dist }
+func (h candidateHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *candidateHeap) Push(x any)        { *h = append(*h, x.(candidate)) }
+
+func (h *candidateHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
@@ -0,0 +1,125 @@
+package airports
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestByIATA(t *testing.T) {
+	a, ok := ByIATA("lax")
+	if !ok {
+		t.Fatal("Expected LAX to be found")
+	}
+
+	if a.ICAO != "KLAX" {
+		t.Errorf("Expected KLAX, got %s", a.ICAO)
+	}
+
+	if _, ok := ByIATA("???"); ok {
+		t.Error("Expected invalid code not to be found")
+	}
+}
+
+func TestSearch(t *testing.T) {
+	testCases := []struct {
+		query    string
+		expected string
+	}{
+		{"heathrow", "EGLL"},
+		{"London Heathrow", "EGLL"},
+		{"heathrw", "EGLL"},
+		{"lhr", "EGLL"},
+		{"merignac", "LFBD"},
+		{"sheremetyevo", "UUEE"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.query, func(t *testing.T) {
+			results := Search(tc.query)
+			if len(results) == 0 {
+				t.Fatal("Expected at least one result")
+			}
+
+			if results[0].ICAO != tc.expected {
+				t.Errorf("Expected %s, got %s", tc.expected, results[0].ICAO)
+			}
+		})
+	}
+
+	if results := Search("xyzzy"); len(results) != 0 {
+		t.Errorf("Expected no results, got %d", len(results))
+	}
+}
+
+func TestWithinOneEdit(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected bool
+	}{
+		{"heathrow", "heathrow", true},
+		{"heathrw", "heathrow", true},
+		{"heathrow", "heathrov", true},
+		{"heathrowx", "heathrow", true},
+		{"heatrw", "heathrow", false},
+		{"gatwick", "heathrow", false},
+	}
+
+	for _, tc := range testCases {
+		if got := withinOneEdit(tc.a, tc.b); got != tc.expected {
+			t.Errorf("withinOneEdit(%q, %q): expected %t, got %t", tc.a, tc.b, tc.expected, got)
+		}
+	}
+}
+
+func TestNearest(t *testing.T) {
+	results := Nearest(51.4706, -0.4619, 1)
+	if len(results) != 1 || results[0].ICAO != "EGLL" {
+		t.Errorf("Expected EGLL, got %v", results)
+	}
+}
+
+// TestKDTree checks the k-d tree against a brute-force search
+func TestKDTree(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+
+	list := make([]Airport, 2000)
+	for i := range list {
+		list[i] = Airport{
+			ICAO:      string(rune('A'+i%26)) + string(rune('A'+i/26%26)) + string(rune('A'+i/676)),
+			Latitude:  rnd.Float64()*180 - 90,
+			Longitude: rnd.Float64()*360 - 180,
+		}
+	}
+	tree := newKDTree(list)
+
+	for i := 0; i < 100; i++ {
+		lat, lon := rnd.Float64()*180-90, rnd.Float64()*360-180
+
+		dists := make(map[string]float64, len(list))
+		for _, a := range list {
+			dists[a.ICAO] = Distance(lat, lon, a.Latitude, a.Longitude)
+		}
+
+		expected := append([]Airport(nil), list...)
+		sort.Slice(expected, func(i, j int) bool {
+			return dists[expected[i].ICAO] < dists[expected[j].ICAO]
+		})
+
+		results := tree.nearest(lat, lon, 5)
+		for j := range results {
+			if results[j].ICAO != expected[j].ICAO {
+				t.Fatalf("%f, %f: result %d: expected %s, got %s", lat, lon, j, expected[j].ICAO, results[j].ICAO)
+			}
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	// Heathrow to Los Angeles
+	d := Distance(51.4706, -0.4619, 33.9425, -118.4080)
+	if math.Abs(d-8760) > 10 {
+		t.Errorf("Expected about 8760 km, got %f", d)
+	}
+}
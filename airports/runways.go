@@ -0,0 +1,54 @@
+package airports
+
+import (
+	_ "embed"
+	"encoding/json"
+	"sync"
+)
+
+//go:generate go run ./internal/genrunways -o runways.json runways.csv
+
+// Runway represents one end of a runway.
+type Runway struct {
+	// Ident is the designator of the runway end, such as "09L".
+	Ident string `json:"ident"`
+
+	// Heading is the true heading of the runway end in degrees.
+	// It's zero if the heading isn't known.
+	Heading float64 `json:"heading,omitempty"`
+
+	// Length is the length of the runway in feet.
+	Length int `json:"length,omitempty"`
+
+	// Width is the width of the runway in feet.
+	Width int `json:"width,omitempty"`
+
+	// Surface is the surface code of the runway, such as "ASP" or "GRS".
+	Surface string `json:"surface,omitempty"`
+
+	// Lighted indicates whether the runway has lighting.
+	Lighted bool `json:"lighted,omitempty"`
+}
+
+// runwaysJSON contains the runway ends at each airport, keyed by ICAO
+// identifier. It's generated by internal/genrunways from runways.csv,
+// which uses the format of the file published by OurAirports. Its
+// validity is checked by the tests, so it's not checked again when
+// it's loaded.
+//
+//go:embed runways.json
+var runwaysJSON []byte
+
+var (
+	runwaysOnce sync.Once
+	runways     map[string][]Runway
+)
+
+// Runways returns the open runway ends at the airport. It returns
+// nil if there's no runway data for the airport.
+func (a Airport) Runways() []Runway {
+	runwaysOnce.Do(func() {
+		_ = json.Unmarshal(runwaysJSON, &runways)
+	})
+	return runways[a.ICAO]
+}
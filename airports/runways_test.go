@@ -0,0 +1,47 @@
+package airports
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// TestRunwaysData checks that the embedded runway dataset is valid,
+// since Runways doesn't report errors when loading it.
+func TestRunwaysData(t *testing.T) {
+	dec := json.NewDecoder(bytes.NewReader(runwaysJSON))
+	dec.DisallowUnknownFields()
+
+	var data map[string][]Runway
+	if err := dec.Decode(&data); err != nil {
+		t.Fatalf("Invalid runway dataset: %s", err)
+	}
+
+	if len(data) == 0 {
+		t.Fatal("Runway dataset is empty, run go generate ./airports")
+	}
+
+	if rwys := Airports["EGLL"].Runways(); len(rwys) == 0 {
+		t.Error("Expected runways for EGLL")
+	}
+
+	for icao, rwys := range data {
+		if _, ok := Airports[icao]; !ok {
+			t.Errorf("Runways for unknown airport %s", icao)
+		}
+
+		for _, rwy := range rwys {
+			if rwy.Ident == "" {
+				t.Errorf("Runway without designator at %s", icao)
+			}
+
+			if rwy.Heading < 0 || rwy.Heading >= 360 {
+				t.Errorf("Invalid heading %g for runway %s at %s", rwy.Heading, rwy.Ident, icao)
+			}
+
+			if rwy.Length < 0 || rwy.Width < 0 {
+				t.Errorf("Invalid dimensions for runway %s at %s", rwy.Ident, icao)
+			}
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package taf
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrOffline is wrapped by the error returned by Cache, along with
+// ErrNotFound, when it's offline and there's no cached report for an airport.
+var ErrOffline = errors.New("taf: offline mode is enabled")
+
+// DefaultCacheDir returns the default directory used by Cache,
+// which is $XDG_CACHE_HOME/taf on Linux.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "taf"), nil
+}
+
+// Cache is a Source that stores the raw text of the reports fetched from
+// another source on disk. Cached reports are used until they're no longer
+// valid or they're older than MaxAge.
+type Cache struct {
+	// Source is the source used to fetch reports that aren't cached.
+	Source RawSource
+
+	// Dir is the directory in which reports are cached. If it's
+	// empty, the directory returned by DefaultCacheDir is used.
+	Dir string
+
+	// MaxAge is the maximum age of a cached report. If it's zero,
+	// cached reports are used until the end of their validity period.
+	MaxAge time.Duration
+
+	// Offline specifies that only cached reports should be used, even
+	// if they're no longer valid. Source isn't used in offline mode.
+	Offline bool
+
+	// Options contains the options used to decode the reports.
+	Options Options
+}
+
+// cacheEntry is the format in which reports are stored on disk
+type cacheEntry struct {
+	Raw       string    `json:"raw"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// Fetch gets and decodes the reports for the given ICAO identifiers,
+// using cached reports when possible.
+func (c *Cache) Fetch(ctx context.Context, icao ...string) ([]*Forecast, error) {
+	entries, err := c.fetch(ctx, icao)
+	errs := []error{err}
+
+	var out []*Forecast
+	for _, id := range upperAll(icao) {
+		entry, ok := entries[id]
+		if !ok {
+			continue
+		}
+
+		fc, err := DecodeWithOptions(strings.NewReader(entry.Raw), c.decodeOptions(entry))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		out = append(out, fc)
+	}
+
+	return out, errors.Join(errs...)
+}
+
+// FetchRaw gets the raw text of the reports for the given ICAO
+// identifiers, using cached reports when possible.
+func (c *Cache) FetchRaw(ctx context.Context, icao ...string) (map[string]string, error) {
+	entries, err := c.fetch(ctx, icao)
+
+	out := make(map[string]string, len(entries))
+	for id, entry := range entries {
+		out[id] = entry.Raw
+	}
+	return out, err
+}
+
+// fetch gets the entries for the given identifiers, from the
+// cache if possible and from the source otherwise. Entries
+// fetched from the source are stored in the cache.
+func (c *Cache) fetch(ctx context.Context, icao []string) (map[string]cacheEntry, error) {
+	dir, err := c.dir()
+	if err != nil {
+		return nil, err
+	}
+
+	out := map[string]cacheEntry{}
+
+	var missing []string
+	for _, id := range upperAll(icao) {
+		// Identifiers are used as file names, so make sure
+		// they can't refer to files outside the cache.
+		if id == "" || strings.ContainsAny(id, `./\`) {
+			return nil, fmt.Errorf("taf: invalid identifier %q", id)
+		}
+
+		entry, err := c.load(dir, id)
+		if err == nil && (c.Offline || c.fresh(entry)) {
+			out[id] = entry
+			continue
+		}
+		missing = append(missing, id)
+	}
+
+	if len(missing) == 0 {
+		return out, nil
+	}
+
+	if c.Offline {
+		return out, fmt.Errorf("%w: %w: %s", ErrNotFound, ErrOffline, strings.Join(missing, ", "))
+	}
+
+	if c.Source == nil {
+		return out, errors.New("taf: cache has no source")
+	}
+
+	fetched, err := c.Source.FetchRaw(ctx, missing...)
+	now := time.Now().UTC()
+	for id, raw := range fetched {
+		entry := cacheEntry{Raw: raw, FetchedAt: now}
+		out[id] = entry
+
+		if serr := c.store(dir, id, entry); serr != nil {
+			err = errors.Join(err, serr)
+		}
+	}
+
+	return out, err
+}
+
+// fresh checks whether a cached entry can still be used
+func (c *Cache) fresh(entry cacheEntry) bool {
+	now := time.Now()
+	if c.MaxAge != 0 && now.Sub(entry.FetchedAt) > c.MaxAge {
+		return false
+	}
+
+	fc, err := DecodeWithOptions(strings.NewReader(entry.Raw), c.decodeOptions(entry))
+	if err != nil {
+		return false
+	}
+
+	return fc.Valid.To.IsZero() || now.Before(fc.Valid.To)
+}
+
+// decodeOptions returns the options used to decode a cached entry
+func (c *Cache) decodeOptions(entry cacheEntry) Options {
+	opts := c.Options
+	if opts.Reference.IsZero() && opts.Year == 0 && opts.Month == 0 {
+		opts.Reference = entry.FetchedAt
+	}
+	return opts
+}
+
+// dir returns the cache directory
+func (c *Cache) dir() (string, error) {
+	if c.Dir != "" {
+		return c.Dir, nil
+	}
+	return DefaultCacheDir()
+}
+
+// load reads the cached entry for the given identifier
+func (c *Cache) load(dir, id string) (cacheEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, id+".json"))
+	if err != nil {
+		return cacheEntry{}, err
+	}
+
+	var entry cacheEntry
+	err = json.Unmarshal(data, &entry)
+	return entry, err
+}
+
+// store writes the cached entry for the given identifier. The entry is
+// written to a temporary file first so that readers never see a partial entry.
+func (c *Cache) store(dir, id string, entry cacheEntry) error {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, id+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, id+".json"))
+}
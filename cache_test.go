@@ -0,0 +1,77 @@
+package taf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// countingSource is a RawSource that counts the
+// number of times reports are fetched from it
+type countingSource struct {
+	FileSource
+	fetches int
+}
+
+func (cs *countingSource) FetchRaw(ctx context.Context, icao ...string) (map[string]string, error) {
+	cs.fetches++
+	return cs.FileSource.FetchRaw(ctx, icao...)
+}
+
+func TestCache(t *testing.T) {
+	srcDir, cacheDir := t.TempDir(), t.TempDir()
+
+	// Generate a report that's valid for the next day
+	now := time.Now().UTC()
+	end := now.Add(24 * time.Hour)
+	report := fmt.Sprintf("TAF EGLL %s %s/%s 22008KT 9999 FEW040",
+		now.Format("021504Z"), now.Format("0215"), end.Format("0215"))
+
+	err := os.WriteFile(filepath.Join(srcDir, "EGLL.txt"), []byte(report), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := &countingSource{FileSource: FileSource{Path: srcDir}}
+	c := &Cache{Source: src, Dir: cacheDir}
+
+	for i := 0; i < 2; i++ {
+		fcs, err := c.Fetch(context.Background(), "EGLL")
+		if err != nil {
+			t.Fatalf("Error fetching reports: %s", err)
+		}
+
+		if len(fcs) != 1 || fcs[0].Identifier != "EGLL" {
+			t.Fatalf("Expected EGLL report, got %d reports", len(fcs))
+		}
+	}
+
+	if src.fetches != 1 {
+		t.Errorf("Expected report to be fetched once, got %d fetches", src.fetches)
+	}
+
+	// A report older than the maximum age should be fetched again
+	c.MaxAge = time.Nanosecond
+	if _, err = c.Fetch(context.Background(), "EGLL"); err != nil {
+		t.Fatalf("Error fetching reports: %s", err)
+	}
+
+	if src.fetches != 2 {
+		t.Errorf("Expected report to be fetched again, got %d fetches", src.fetches)
+	}
+
+	// Offline mode should only use the cache, even for old reports
+	offline := &Cache{Dir: cacheDir, Offline: true, MaxAge: time.Nanosecond}
+	fcs, err := offline.Fetch(context.Background(), "EGLL", "KJFK")
+	if !errors.Is(err, ErrOffline) || !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrOffline and ErrNotFound for uncached report, got %v", err)
+	}
+
+	if len(fcs) != 1 || fcs[0].Identifier != "EGLL" {
+		t.Errorf("Expected cached EGLL report, got %d reports", len(fcs))
+	}
+}
@@ -0,0 +1,253 @@
+package taf
+
+import (
+	"slices"
+
+	"go.elara.ws/taf/units"
+)
+
+// FlightCategory represents a category of flight conditions,
+// determined by the ceiling and visibility.
+type FlightCategory string
+
+// FAA Flight Categories
+const (
+	// VFR represents visual flight rules conditions.
+	VFR FlightCategory = "VFR"
+	// MVFR represents marginal visual flight rules conditions.
+	MVFR FlightCategory = "MVFR"
+	// IFR represents instrument flight rules conditions.
+	IFR FlightCategory = "IFR"
+	// LIFR represents low instrument flight rules conditions.
+	LIFR FlightCategory = "LIFR"
+)
+
+// Military Color States
+const (
+	Blue    FlightCategory = "Blue"
+	White   FlightCategory = "White"
+	Green   FlightCategory = "Green"
+	Yellow1 FlightCategory = "Yellow1"
+	Yellow2 FlightCategory = "Yellow2"
+	Amber   FlightCategory = "Amber"
+	Red     FlightCategory = "Red"
+)
+
+// CategoryRule describes the conditions under which a flight category applies.
+type CategoryRule struct {
+	// Category is the flight category this rule describes.
+	Category FlightCategory
+
+	// Ceiling is the ceiling below which this category applies.
+	Ceiling units.Height
+
+	// Visibility is the visibility below which this category applies.
+	Visibility Visibility
+
+	// Inclusive indicates whether values equal to the limits
+	// also fall into this category.
+	Inclusive bool
+}
+
+// CategoryTable describes a set of flight categories.
+type CategoryTable struct {
+	// Rules contains the rules for each category, from the worst
+	// conditions to the best. The first matching rule is used.
+	Rules []CategoryRule
+
+	// Default is the category used when none of the rules match.
+	Default FlightCategory
+
+	// Scattered indicates whether scattered layers should be considered
+	// when finding the ceiling, as they are for military color states.
+	Scattered bool
+}
+
+// FAACategories contains the FAA flight categories
+var FAACategories = CategoryTable{
+	Rules: []CategoryRule{
+		{Category: LIFR, Ceiling: units.HeightFeet(500), Visibility: Visibility{Length: units.Length{Value: 1, Unit: units.Miles}}},
+		{Category: IFR, Ceiling: units.HeightFeet(1000), Visibility: Visibility{Length: units.Length{Value: 3, Unit: units.Miles}}},
+		{Category: MVFR, Ceiling: units.HeightFeet(3000), Visibility: Visibility{Length: units.Length{Value: 5, Unit: units.Miles}}, Inclusive: true},
+	},
+	Default: VFR,
+}
+
+// ColorStates contains the military color states used by NATO air forces
+var ColorStates = CategoryTable{
+	Rules: []CategoryRule{
+		{Category: Red, Ceiling: units.HeightFeet(200), Visibility: Visibility{Length: units.Length{Value: 800, Unit: units.Meters}}},
+		{Category: Amber, Ceiling: units.HeightFeet(300), Visibility: Visibility{Length: units.Length{Value: 1600, Unit: units.Meters}}},
+		{Category: Yellow2, Ceiling: units.HeightFeet(500), Visibility: Visibility{Length: units.Length{Value: 2500, Unit: units.Meters}}},
+		{Category: Yellow1, Ceiling: units.HeightFeet(700), Visibility: Visibility{Length: units.Length{Value: 3700, Unit: units.Meters}}},
+		{Category: Green, Ceiling: units.HeightFeet(1500), Visibility: Visibility{Length: units.Length{Value: 5000, Unit: units.Meters}}},
+		{Category: White, Ceiling: units.HeightFeet(2500), Visibility: Visibility{Length: units.Length{Value: 8000, Unit: units.Meters}}},
+	},
+	Default:   Blue,
+	Scattered: true,
+}
+
+// cavokVisibility is the minimum visibility implied by CAVOK
+var cavokVisibility = Visibility{Plus: true, Length: units.Length{Value: 10, Unit: units.Kilometers}}
+
+// Ceiling returns the height of the lowest broken,
+// overcast, or vertical visibility layer. If there's no such layer,
+// ok is false.
+func (fc *Forecast) Ceiling() (altitude units.Height, ok bool) {
+	return ceiling(fc.SkyCondition, false)
+}
+
+// FlightCategory returns the FAA flight category of the base forecast.
+func (fc *Forecast) FlightCategory() FlightCategory {
+	return fc.Category(FAACategories)
+}
+
+// Category returns the flight category of the base forecast
+// according to the given table.
+func (fc *Forecast) Category(table CategoryTable) FlightCategory {
+	return categorize(table, fc.Visibility, fc.SkyCondition, fc.Flags)
+}
+
+// Ceiling returns the height of the lowest broken,
+// overcast, or vertical visibility layer in the change. If there's
+// no such layer, ok is false.
+func (ch *Change) Ceiling() (altitude units.Height, ok bool) {
+	return ceiling(ch.SkyCondition, false)
+}
+
+// FlightCategory returns the FAA flight category of the change.
+// Only the groups within the change are considered.
+func (ch *Change) FlightCategory() FlightCategory {
+	return ch.Category(FAACategories)
+}
+
+// Category returns the flight category of the change according to
+// the given table. Only the groups within the change are considered.
+func (ch *Change) Category(table CategoryTable) FlightCategory {
+	return categorize(table, ch.Visibility, ch.SkyCondition, ch.Flags)
+}
+
+// Ceiling returns the height of the lowest broken,
+// overcast, or vertical visibility layer in the probability group.
+// If there's no such layer, ok is false.
+func (pr *Probability) Ceiling() (altitude units.Height, ok bool) {
+	return ceiling(pr.SkyCondition, false)
+}
+
+// FlightCategory returns the FAA flight category of the probability group.
+// Only the groups within the probability group are considered.
+func (pr *Probability) FlightCategory() FlightCategory {
+	return pr.Category(FAACategories)
+}
+
+// Category returns the flight category of the probability group according
+// to the given table. Only the groups within the probability group are considered.
+func (pr *Probability) Category(table CategoryTable) FlightCategory {
+	return categorize(table, pr.Visibility, pr.SkyCondition, pr.Flags)
+}
+
+// Ceiling returns the height of the lowest broken,
+// overcast, or vertical visibility layer. If there's no such layer,
+// ok is false.
+func (c Conditions) Ceiling() (altitude units.Height, ok bool) {
+	return ceiling(c.SkyCondition, false)
+}
+
+// FlightCategory returns the FAA flight category of the prevailing conditions.
+func (c Conditions) FlightCategory() FlightCategory {
+	return c.Category(FAACategories)
+}
+
+// Category returns the flight category of the prevailing
+// conditions according to the given table.
+func (c Conditions) Category(table CategoryTable) FlightCategory {
+	return categorize(table, c.Visibility, c.SkyCondition, c.Flags)
+}
+
+// Ceiling returns the height of the lowest broken,
+// overcast, or vertical visibility layer. If there's no such layer,
+// ok is false.
+func (a Alternate) Ceiling() (altitude units.Height, ok bool) {
+	return ceiling(a.SkyCondition, false)
+}
+
+// FlightCategory returns the FAA flight category of the alternate conditions.
+func (a Alternate) FlightCategory() FlightCategory {
+	return a.Category(FAACategories)
+}
+
+// Category returns the flight category of the alternate
+// conditions according to the given table.
+func (a Alternate) Category(table CategoryTable) FlightCategory {
+	return categorize(table, a.Visibility, a.SkyCondition, a.Flags)
+}
+
+// ceiling finds the lowest layer that counts as a ceiling. If scattered
+// is true, scattered layers are counted as well.
+func ceiling(sky []SkyCondition, scattered bool) (altitude units.Height, ok bool) {
+	for _, sc := range sky {
+		switch sc.Type {
+		case Broken, Overcast, VerticalVisibility:
+		case Scattered:
+			if !scattered {
+				continue
+			}
+		default:
+			continue
+		}
+
+		if !ok || sc.Altitude.Less(altitude) {
+			altitude, ok = sc.Altitude, true
+		}
+	}
+	return altitude, ok
+}
+
+// categorize determines the flight category of a set of groups. If neither
+// the ceiling nor the visibility is known, it returns an empty string.
+func categorize(table CategoryTable, vis Visibility, sky []SkyCondition, flags []Flag) FlightCategory {
+	cig, hasCig := ceiling(sky, table.Scattered)
+
+	if slices.Contains(flags, CeilingAndVisibilityOK) {
+		vis = cavokVisibility
+	}
+
+	hasVis := vis.Unit != ""
+	if !hasCig && !hasVis && len(sky) == 0 {
+		return ""
+	}
+
+	for _, rule := range table.Rules {
+		if hasCig && (cig.Less(rule.Ceiling) || (rule.Inclusive && !rule.Ceiling.Less(cig))) {
+			return rule.Category
+		}
+
+		if hasVis && visibilityBelow(vis, rule.Visibility, rule.Inclusive) {
+			return rule.Category
+		}
+	}
+
+	return table.Default
+}
+
+// visibilityBelow checks whether v is below the limit, taking
+// the units and the plus and minus indicators into account.
+func visibilityBelow(v, limit Visibility, inclusive bool) bool {
+	val := v.In(units.Meters)
+	lim := limit.In(units.Meters)
+
+	switch {
+	case val < lim:
+		return true
+	case val > lim:
+		return false
+	case v.Plus:
+		// The visibility is more than the limit
+		return false
+	case v.Minus:
+		// The visibility is less than the limit
+		return true
+	default:
+		return inclusive
+	}
+}
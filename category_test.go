@@ -0,0 +1,54 @@
+package taf
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.elara.ws/taf/units"
+)
+
+func TestFlightCategory(t *testing.T) {
+	const data = `TAF UUEE 211958Z 2121/2221 VRB01MPS 9999 SCT030 TX20/2212Z TN12/2202Z
+  TEMPO 2121/2204 BKN004
+  PROB40
+  TEMPO 2121/2204 0300 FG
+  BECMG 2204/2206 24006MPS
+  FM221200 18005KT 4SM BKN020
+  FM221500 18005KT CAVOK`
+
+	fc, err := DecodeWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	if cat := fc.FlightCategory(); cat != VFR {
+		t.Errorf("Expected base forecast to be VFR, got %q", cat)
+	}
+
+	if cat := fc.Category(ColorStates); cat != Blue {
+		t.Errorf("Expected base forecast to be Blue, got %q", cat)
+	}
+
+	if _, ok := fc.Ceiling(); ok {
+		t.Error("Expected base forecast to have no ceiling")
+	}
+
+	expected := []FlightCategory{LIFR, LIFR, "", MVFR, VFR}
+	for i, ch := range fc.Changes {
+		if cat := ch.FlightCategory(); cat != expected[i] {
+			t.Errorf("Expected change %d to be %q, got %q", i, expected[i], cat)
+		}
+	}
+
+	if cig, ok := fc.Changes[3].Ceiling(); !ok || cig != units.HeightFeet(2000) {
+		t.Errorf("Expected ceiling of 2000 ft, got %s", cig)
+	}
+
+	if cat := fc.Changes[3].Category(ColorStates); cat != White {
+		t.Errorf("Expected change to be White, got %q", cat)
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/pflag"
+	"go.elara.ws/logger/log"
+	"go.elara.ws/taf"
+)
+
+// diff compares two TAF reports and prints the differences
+func diff(args []string) {
+	fs := pflag.NewFlagSet("diff", pflag.ExitOnError)
+	format := fs.StringP("format", "f", "text", "Output format (valid formats: json, text)")
+	pretty := fs.BoolP("pretty", "p", true, "Pretty-print the JSON output")
+	lenient := fs.BoolP("lenient", "l", false, "Skip groups that can't be decoded instead of failing")
+	fs.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: tafparser diff [flags] <old> <new>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	if *format != "json" && *format != "text" {
+		log.Fatal("Invalid output format").Str("format", *format).Send()
+	}
+
+	opts := taf.Options{Lenient: *lenient}
+	oldFc := decodeFile(fs.Arg(0), opts)
+	newFc := decodeFile(fs.Arg(1), opts)
+
+	diffs := taf.Diff(oldFc, newFc)
+
+	if *format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		if *pretty {
+			enc.SetIndent("", "  ")
+		}
+
+		if diffs == nil {
+			diffs = []taf.Difference{}
+		}
+
+		err := enc.Encode(diffs)
+		if err != nil {
+			log.Fatal("Error encoding differences").Err(err).Send()
+		}
+		return
+	}
+
+	if len(diffs) == 0 {
+		fmt.Println("No differences.")
+		return
+	}
+
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+}
+
+// decodeFile decodes the TAF report in the file at path
+func decodeFile(path string, opts taf.Options) *taf.Forecast {
+	fl, err := os.Open(path)
+	if err != nil {
+		log.Fatal("Error opening file").Str("path", path).Err(err).Send()
+	}
+	defer fl.Close()
+
+	fc, err := taf.DecodeWithOptions(fl, opts)
+	if err != nil {
+		log.Fatal("Error parsing TAF data").Str("path", path).Err(err).Send()
+	}
+	return fc
+}
@@ -1,17 +1,21 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
-	"io"
-	"net/http"
+	"errors"
+	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/alecthomas/repr"
 	"github.com/spf13/pflag"
 	"go.elara.ws/logger"
 	"go.elara.ws/logger/log"
 	"go.elara.ws/taf"
+	"go.elara.ws/taf/airports"
+	"go.elara.ws/taf/i18n"
 	"go.elara.ws/taf/units"
 )
 
@@ -20,73 +24,286 @@ func init() {
 }
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "serve":
+			serve(os.Args[2:])
+			return
+		case "diff":
+			diff(os.Args[2:])
+			return
+		}
+	}
+
 	pretty := pflag.BoolP("pretty", "p", true, "Pretty-print the JSON output")
 	printGo := pflag.BoolP("print-go", "G", false, "Print Go code instead of JSON")
-	convertDist := pflag.StringP("convert-distance", "d", "", "Convert all the distances to the given unit. (valid units: mi, m, km)")
-	convertSpd := pflag.StringP("convert-speed", "s", "", "Convert all the speeds to the given unit. (valid units: m/s, kph, kts, mph)")
-	identifier := pflag.StringP("identifier", "i", "", "Automatically fetch the TAF report for the specified ICAO identifier")
+	format := pflag.StringP("format", "f", "json", "Output format (valid formats: json, text)")
+	localTime := pflag.Bool("local-time", false, "Show times in the airport's local timezone in text output")
+	lang := pflag.String("lang", "", "Add labels in the given language to the JSON output (e.g. en, fr, de, es)")
+	pflag.StringP("units", "U", "", "Convert all the units to the given unit system, which the other conversion flags override. (valid systems: metric, icao, us, si)")
+	pflag.StringP("convert-distance", "d", "", "Convert all the distances to the given unit. (valid units: mi, m, km, ft)")
+	pflag.StringP("convert-speed", "s", "", "Convert all the speeds to the given unit. (valid units: m/s, kph, kts, mph)")
+	pflag.StringP("convert-temperature", "t", "", "Convert all the temperatures to the given unit. (valid units: c, f, k)")
+	pflag.StringP("convert-altimeter", "a", "", "Convert all the altimeter settings to the given unit. (valid units: hpa, inhg, mmhg)")
+	pflag.StringP("convert-height", "H", "", "Convert all the heights, such as cloud bases, to the given unit. (valid units: ft, m, fl)")
+	pflag.String("rounding", "nearest", "How converted speeds and temperatures are rounded (valid modes: nearest, truncate, ceil, floor)")
+	identifier := pflag.StringP("identifier", "i", "", "Automatically fetch the TAF report for the specified airport (ICAO code, IATA code, or name)")
+	sourceURL := pflag.StringP("source-url", "u", taf.DefaultAviationWeatherURL, "URL of the endpoint used to fetch TAF reports")
+	useCache := pflag.BoolP("cache", "c", false, "Cache fetched TAF reports until they're no longer valid")
+	maxAge := pflag.Duration("cache-max-age", 0, "Maximum age of cached TAF reports (0 means until they're no longer valid)")
+	offline := pflag.Bool("offline", false, "Only use cached TAF reports, even if they're no longer valid")
+	lenient := pflag.BoolP("lenient", "l", false, "Skip groups that can't be decoded instead of failing")
+	spans := pflag.Bool("spans", false, "Include the position of each group in the original text in JSON output")
+	validate := pflag.Bool("validate", false, "Check that the TAF report conforms to the ruleset instead of printing it, and exit with status 1 if it doesn't")
+	rulesetName := pflag.String("ruleset", "annex3", "Ruleset used by --validate (valid rulesets: annex3, fmh1)")
 	pflag.Parse()
 
-	var opts taf.Options
+	if *format != "json" && *format != "text" {
+		log.Fatal("Invalid output format").Str("format", *format).Send()
+	}
 
-	if *convertDist != "" {
-		d, ok := units.ParseDistance(*convertDist)
-		if !ok {
-			log.Fatal("Invalid distance unit").Send()
-		}
-		opts.DistanceUnit = d
+	ruleset, ok := taf.ParseRuleset(*rulesetName)
+	if !ok {
+		log.Fatal("Invalid ruleset").Str("ruleset", *rulesetName).Send()
 	}
 
-	if *convertSpd != "" {
-		s, ok := units.ParseSpeed(*convertSpd)
-		if !ok {
-			log.Fatal("Invalid speed unit").Send()
-		}
-		opts.SpeedUnit = s
+	opts := taf.Options{Lenient: *lenient, Spans: *spans}
+
+	us, err := unitSystem(func(names ...string) string {
+		return pflag.Lookup(names[0]).Value.String()
+	})
+	if err != nil {
+		log.Fatal("Invalid unit conversion").Err(err).Send()
 	}
 
-	var r io.Reader
-	var err error
+	var fc *taf.Forecast
 	if pflag.NArg() > 0 {
 		fl, err := os.Open(pflag.Arg(0))
 		if err != nil {
 			log.Fatal("Error opening file").Err(err).Send()
 		}
 		defer fl.Close()
-		r = fl
+		fc, err = taf.DecodeWithOptions(fl, opts)
+		if err != nil {
+			log.Fatal("Error parsing TAF data").Err(err).Send()
+		}
 	} else if *identifier != "" {
-		// Identifiers must be uppercase
-		*identifier = strings.ToUpper(*identifier)
-		// Get the TAF report from aviationweather.gov's beta endpoint
-		res, err := http.Get("https://aviationweather.gov/cgi-bin/data/taf.php?ids=" + *identifier)
+		aw := newSource(*sourceURL, opts)
+
+		var src taf.Source = aw
+		if *useCache || *offline {
+			src = &taf.Cache{
+				Source:  aw,
+				MaxAge:  *maxAge,
+				Offline: *offline,
+				Options: opts,
+			}
+		}
+
+		icao, err := resolveAirport(*identifier)
 		if err != nil {
-			log.Fatal("Error getting TAF report").Err(err).Send()
+			log.Fatal("Couldn't find the specified airport").Str("id", *identifier).Send()
 		}
-		// The backend doesn't return an error for non-existent reports, so check the content length instead
-		if res.ContentLength == 0 {
-			log.Fatal("Couldn't find a TAF report for the specified airport").Str("id", *identifier).Send()
+
+		fcs, err := src.Fetch(context.Background(), icao)
+		if errors.Is(err, taf.ErrOffline) {
+			log.Fatal("Couldn't find a cached TAF report for the specified airport").Str("id", icao).Send()
+		} else if errors.Is(err, taf.ErrNotFound) {
+			log.Fatal("Couldn't find a TAF report for the specified airport").Str("id", icao).Send()
+		} else if err != nil {
+			log.Fatal("Error getting TAF report").Err(err).Send()
 		}
-		defer res.Body.Close()
-		r = res.Body
+		fc = fcs[0]
 	} else {
-		r = os.Stdin
+		fc, err = taf.DecodeWithOptions(os.Stdin, opts)
+		if err != nil {
+			log.Fatal("Error parsing TAF data").Err(err).Send()
+		}
 	}
+	fc = fc.ConvertUnits(us)
 
-	fc, err := taf.DecodeWithOptions(r, opts)
-	if err != nil {
-		log.Fatal("Error parsing TAF data").Err(err).Send()
+	if *validate {
+		printViolations(taf.Validate(fc, ruleset), *format, *pretty)
+		return
 	}
 
 	if *printGo {
 		repr.New(os.Stdout, repr.ScalarLiterals()).Println(fc)
+	} else if *format == "text" {
+		fmt.Println(fc.Describe(taf.DescribeOptions{LocalTime: *localTime}))
 	} else {
 		enc := json.NewEncoder(os.Stdout)
 		if *pretty {
 			enc.SetIndent("", "  ")
 		}
-		err = enc.Encode(fc)
+
+		var out any = fc
+		if *lang != "" {
+			out = labeledForecast{Forecast: fc, Labels: i18n.Labels(fc, *lang)}
+		}
+
+		err = enc.Encode(out)
 		if err != nil {
 			log.Fatal("Error encoding forecast").Err(err).Send()
 		}
 	}
 }
+
+// printViolations prints the violations found by --validate and
+// exits with status 1 if any of them are errors
+func printViolations(violations []taf.Violation, format string, pretty bool) {
+	if format == "text" {
+		for _, v := range violations {
+			fmt.Println(v)
+		}
+	} else {
+		enc := json.NewEncoder(os.Stdout)
+		if pretty {
+			enc.SetIndent("", "  ")
+		}
+
+		if violations == nil {
+			violations = []taf.Violation{}
+		}
+
+		err := enc.Encode(violations)
+		if err != nil {
+			log.Fatal("Error encoding violations").Err(err).Send()
+		}
+	}
+
+	for _, v := range violations {
+		if v.Severity == taf.SeverityError {
+			os.Exit(1)
+		}
+	}
+}
+
+// labeledForecast is the JSON output used when a language
+// is selected with the --lang flag
+type labeledForecast struct {
+	Forecast *taf.Forecast     `json:"forecast"`
+	Labels   map[string]string `json:"labels"`
+}
+
+// resolveAirport converts an ICAO code, IATA code, or airport
+// name to the ICAO code used to fetch reports
+func resolveAirport(id string) (string, error) {
+	id = strings.TrimSpace(id)
+	upper := strings.ToUpper(id)
+
+	if _, ok := airports.Airports[upper]; ok {
+		return upper, nil
+	}
+
+	if a, ok := airports.ByIATA(id); ok {
+		log.Info("Resolved IATA code").Str("iata", a.IATA).Str("icao", a.ICAO).Send()
+		return a.ICAO, nil
+	}
+
+	// The airports dataset doesn't include every airport that
+	// publishes TAF reports, so try anything that looks like
+	// an ICAO code anyway, rather than searching for an airport
+	// with a similar name.
+	if isICAOCode(upper) {
+		return upper, nil
+	}
+
+	if results := airports.Search(id); len(results) > 0 {
+		log.Info("Resolved airport name").Str("name", results[0].Name).Str("icao", results[0].ICAO).Send()
+		return results[0].ICAO, nil
+	}
+
+	return "", errors.New("airport not found")
+}
+
+// isICAOCode checks whether s has the format of an ICAO code,
+// which is four uppercase letters or digits.
+func isICAOCode(s string) bool {
+	if len(s) != 4 {
+		return false
+	}
+
+	for _, c := range s {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// newSource creates the source used to fetch reports
+// for the given identifiers
+func newSource(baseURL string, opts taf.Options) *taf.AviationWeather {
+	return &taf.AviationWeather{
+		BaseURL: baseURL,
+		Timeout: 30 * time.Second,
+		Retries: 2,
+		Options: opts,
+	}
+}
+
+// unitSystem creates the unit system that forecasts are converted to from
+// the conversion options. get returns the value of an option given its
+// names. The units option selects a predefined unit system, and the
+// options for individual units override it.
+func unitSystem(get func(names ...string) string) (taf.UnitSystem, error) {
+	var us taf.UnitSystem
+	if name := get("units", "U"); name != "" {
+		var ok bool
+		us, ok = taf.ParseUnitSystem(name)
+		if !ok {
+			return us, errors.New("invalid unit system")
+		}
+	}
+
+	if d := get("convert-distance", "d"); d != "" {
+		unit, ok := units.ParseDistance(d)
+		if !ok {
+			return us, errors.New("invalid distance unit")
+		}
+		us.Distance = unit
+	}
+
+	if s := get("convert-speed", "s"); s != "" {
+		unit, ok := units.ParseSpeed(s)
+		if !ok {
+			return us, errors.New("invalid speed unit")
+		}
+		us.Speed = unit
+	}
+
+	if t := get("convert-temperature", "t"); t != "" {
+		unit, ok := units.ParseTemperature(t)
+		if !ok {
+			return us, errors.New("invalid temperature unit")
+		}
+		us.Temperature = unit
+	}
+
+	if a := get("convert-altimeter", "a"); a != "" {
+		unit, ok := units.ParsePressure(a)
+		if !ok {
+			return us, errors.New("invalid pressure unit")
+		}
+		us.Pressure = unit
+	}
+
+	if h := get("convert-height", "H"); h != "" {
+		unit, ok := units.ParseHeight(h)
+		if !ok {
+			return us, errors.New("invalid height unit")
+		}
+		us.Height = unit
+	}
+
+	if r := get("rounding"); r != "" {
+		rounding, ok := units.ParseRounding(r)
+		if !ok {
+			return us, errors.New("invalid rounding mode")
+		}
+		us.Rounding = rounding
+	}
+
+	return us, nil
+}
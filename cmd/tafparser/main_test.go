@@ -0,0 +1,35 @@
+package main
+
+import "testing"
+
+func TestResolveAirport(t *testing.T) {
+	tests := []struct {
+		id       string
+		expected string
+	}{
+		{"egll", "EGLL"},
+		{"LHR", "EGLL"},
+		{"heathrow", "EGLL"},
+		// ICAO codes are used as-is, even if they aren't in the
+		// airports dataset, rather than matching an airport name.
+		{"BIRK", "BIRK"},
+		{"K1A5", "K1A5"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.id, func(t *testing.T) {
+			icao, err := resolveAirport(test.id)
+			if err != nil {
+				t.Fatalf("Error resolving airport: %s", err)
+			}
+
+			if icao != test.expected {
+				t.Errorf("Expected %s, got %s", test.expected, icao)
+			}
+		})
+	}
+
+	if _, err := resolveAirport("no such airport anywhere"); err == nil {
+		t.Error("Expected unknown airport not to be found")
+	}
+}
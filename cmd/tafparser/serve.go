@@ -0,0 +1,220 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	"go.elara.ws/logger/log"
+	"go.elara.ws/taf"
+)
+
+// maxBodySize is the maximum size of a report accepted by POST /decode
+const maxBodySize = 1 << 20
+
+// serve runs tafparser in HTTP server mode
+func serve(args []string) {
+	fs := pflag.NewFlagSet("serve", pflag.ExitOnError)
+	addr := fs.StringP("addr", "a", ":8080", "Address to listen on")
+	sourceURL := fs.StringP("source-url", "u", taf.DefaultAviationWeatherURL, "URL of the endpoint used to fetch TAF reports")
+	fs.Parse(args)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/decode", handleDecode)
+	mux.HandleFunc("/taf/", func(res http.ResponseWriter, req *http.Request) {
+		handleTAF(res, req, *sourceURL)
+	})
+	mux.HandleFunc("/healthz", handleHealth)
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	log.Info("Starting HTTP server").Str("addr", *addr).Send()
+	err := srv.ListenAndServe()
+	if err != nil {
+		log.Fatal("Error running HTTP server").Err(err).Send()
+	}
+}
+
+// handleDecode decodes the raw TAF report in the request body
+func handleDecode(res http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		res.Header().Set("Allow", http.MethodPost)
+		writeError(res, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	opts, err := optionsFromQuery(req)
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err)
+		return
+	}
+
+	us, err := unitsFromQuery(req)
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err)
+		return
+	}
+
+	decode(res, http.MaxBytesReader(res, req.Body, maxBodySize), opts, us)
+}
+
+// handleTAF fetches and decodes the TAF report for the ICAO
+// identifier in the request path
+func handleTAF(res http.ResponseWriter, req *http.Request, sourceURL string) {
+	if req.Method != http.MethodGet {
+		res.Header().Set("Allow", http.MethodGet)
+		writeError(res, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	icao := strings.TrimPrefix(req.URL.Path, "/taf/")
+	if icao == "" || strings.Contains(icao, "/") {
+		writeError(res, http.StatusNotFound, errors.New("invalid identifier"))
+		return
+	}
+
+	opts, err := optionsFromQuery(req)
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err)
+		return
+	}
+
+	us, err := unitsFromQuery(req)
+	if err != nil {
+		writeError(res, http.StatusBadRequest, err)
+		return
+	}
+
+	fcs, err := newSource(sourceURL, opts).Fetch(req.Context(), icao)
+	if errors.Is(err, taf.ErrNotFound) {
+		writeError(res, http.StatusNotFound, err)
+		return
+	} else if err != nil {
+		var decErr *taf.DecodeError
+		if errors.As(err, &decErr) {
+			writeDecodeError(res, err, decErr)
+			return
+		}
+
+		log.Warn("Error getting TAF report").Str("id", icao).Err(err).Send()
+		writeError(res, http.StatusBadGateway, err)
+		return
+	}
+
+	writeJSON(res, http.StatusOK, fcs[0].ConvertUnits(us))
+}
+
+// handleHealth reports that the server is running
+func handleHealth(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(res, "ok\n")
+}
+
+// decode decodes the report in r, converts it to the
+// given unit system, and writes it as JSON
+func decode(res http.ResponseWriter, r io.Reader, opts taf.Options, us taf.UnitSystem) {
+	fc, err := taf.DecodeWithOptions(r, opts)
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		var decErr *taf.DecodeError
+		switch {
+		case errors.As(err, &maxErr):
+			writeError(res, http.StatusRequestEntityTooLarge, err)
+		case errors.As(err, &decErr):
+			writeDecodeError(res, err, decErr)
+		default:
+			writeError(res, http.StatusUnprocessableEntity, err)
+		}
+		return
+	}
+
+	writeJSON(res, http.StatusOK, fc.ConvertUnits(us))
+}
+
+// optionsFromQuery creates decoding options from the query parameters
+// of a request. The parameters use the same names as the command-line flags.
+func optionsFromQuery(req *http.Request) (taf.Options, error) {
+	var opts taf.Options
+	query := req.URL.Query()
+
+	if l := queryParam(query, "lenient", "l"); l != "" {
+		lenient, err := strconv.ParseBool(l)
+		if err != nil {
+			return opts, errors.New("invalid lenient value")
+		}
+		opts.Lenient = lenient
+	}
+
+	if s := queryParam(query, "spans"); s != "" {
+		spans, err := strconv.ParseBool(s)
+		if err != nil {
+			return opts, errors.New("invalid spans value")
+		}
+		opts.Spans = spans
+	}
+
+	return opts, nil
+}
+
+// unitsFromQuery creates the unit system that forecasts are converted to
+// from the query parameters of a request. The units parameter selects a
+// predefined unit system, and the parameters for individual units
+// override it. The parameters use the same names as the command-line flags.
+func unitsFromQuery(req *http.Request) (taf.UnitSystem, error) {
+	query := req.URL.Query()
+	return unitSystem(func(names ...string) string {
+		return queryParam(query, names...)
+	})
+}
+
+// queryParam returns the value of the first of the given
+// query parameters that's set.
+func queryParam(query url.Values, names ...string) string {
+	for _, name := range names {
+		if vals := query[name]; len(vals) > 0 {
+			return vals[0]
+		}
+	}
+	return ""
+}
+
+// errorResponse is the JSON body returned when a request fails
+type errorResponse struct {
+	Error    string        `json:"error"`
+	Problems []taf.Problem `json:"problems,omitempty"`
+}
+
+// writeError writes an error as a JSON response
+func writeError(res http.ResponseWriter, code int, err error) {
+	writeJSON(res, code, errorResponse{Error: err.Error()})
+}
+
+// writeDecodeError writes an error response listing the
+// problems found while decoding a report
+func writeDecodeError(res http.ResponseWriter, err error, decErr *taf.DecodeError) {
+	writeJSON(res, http.StatusUnprocessableEntity, errorResponse{
+		Error:    err.Error(),
+		Problems: decErr.Problems,
+	})
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func writeJSON(res http.ResponseWriter, code int, v any) {
+	res.Header().Set("Content-Type", "application/json")
+	res.WriteHeader(code)
+
+	err := json.NewEncoder(res).Encode(v)
+	if err != nil {
+		log.Warn("Error encoding response").Err(err).Send()
+	}
+}
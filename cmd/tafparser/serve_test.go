@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.elara.ws/taf"
+	"go.elara.ws/taf/units"
+)
+
+const serveData = "TAF KJFK 212335Z 2200/2306 33012G18KT P6SM FEW060 BKN250"
+
+func TestHandleDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		query    string
+		body     string
+		code     int
+		problems bool
+	}{
+		{name: "ok", method: http.MethodPost, query: "?U=us&lenient=true", body: serveData, code: http.StatusOK},
+		{name: "invalid units", method: http.MethodPost, query: "?units=imperial", body: serveData, code: http.StatusBadRequest},
+		{name: "invalid lenient", method: http.MethodPost, query: "?lenient=maybe", body: serveData, code: http.StatusBadRequest},
+		{name: "method not allowed", method: http.MethodGet, code: http.StatusMethodNotAllowed},
+		{name: "too large", method: http.MethodPost, body: strings.Repeat("X", maxBodySize+1), code: http.StatusRequestEntityTooLarge},
+		{name: "invalid report", method: http.MethodPost, body: serveData + " ?????", code: http.StatusUnprocessableEntity, problems: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, "/decode"+test.query, strings.NewReader(test.body))
+			rec := httptest.NewRecorder()
+			handleDecode(rec, req)
+			checkResponse(t, rec, test.code, test.problems)
+
+			if test.code == http.StatusMethodNotAllowed && rec.Header().Get("Allow") != http.MethodPost {
+				t.Errorf("Expected Allow header to be %q, got %q", http.MethodPost, rec.Header().Get("Allow"))
+			}
+		})
+	}
+}
+
+func TestHandleDecodeUnits(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/decode?U=us", strings.NewReader(serveData))
+	rec := httptest.NewRecorder()
+	handleDecode(rec, req)
+	checkResponse(t, rec, http.StatusOK, false)
+
+	var fc taf.Forecast
+	if err := json.NewDecoder(rec.Body).Decode(&fc); err != nil {
+		t.Fatalf("Error decoding response: %s", err)
+	}
+
+	if fc.Identifier != "KJFK" || fc.Visibility.Unit != units.Miles {
+		t.Errorf("Expected KJFK forecast in US units, got %s with visibility in %q", fc.Identifier, fc.Visibility.Unit)
+	}
+}
+
+func TestHandleTAF(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		switch req.URL.Query().Get("ids") {
+		case "KJFK":
+			res.Write([]byte(serveData))
+		case "KLAX":
+			res.Write([]byte("TAF KLAX 212011Z 2120/2224 26012KT ?????"))
+		case "EGLL":
+			http.Error(res, "bad request", http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		code     int
+		problems bool
+	}{
+		{name: "ok", method: http.MethodGet, path: "/taf/KJFK?U=icao", code: http.StatusOK},
+		{name: "not found", method: http.MethodGet, path: "/taf/XXXX", code: http.StatusNotFound},
+		{name: "invalid identifier", method: http.MethodGet, path: "/taf/", code: http.StatusNotFound},
+		{name: "invalid units", method: http.MethodGet, path: "/taf/KJFK?s=furlongs", code: http.StatusBadRequest},
+		{name: "invalid report", method: http.MethodGet, path: "/taf/KLAX", code: http.StatusUnprocessableEntity, problems: true},
+		{name: "source error", method: http.MethodGet, path: "/taf/EGLL", code: http.StatusBadGateway},
+		{name: "method not allowed", method: http.MethodPost, path: "/taf/KJFK", code: http.StatusMethodNotAllowed},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			req := httptest.NewRequest(test.method, test.path, nil)
+			rec := httptest.NewRecorder()
+			handleTAF(rec, req, srv.URL)
+			checkResponse(t, rec, test.code, test.problems)
+
+			if test.code == http.StatusMethodNotAllowed && rec.Header().Get("Allow") != http.MethodGet {
+				t.Errorf("Expected Allow header to be %q, got %q", http.MethodGet, rec.Header().Get("Allow"))
+			}
+		})
+	}
+}
+
+// checkResponse checks the status code of a response, and that error
+// responses have a JSON body with a message and, if problems is set,
+// the problems found in the report.
+func checkResponse(t *testing.T, rec *httptest.ResponseRecorder, code int, problems bool) {
+	t.Helper()
+
+	if rec.Code != code {
+		t.Fatalf("Expected status %d, got %d: %s", code, rec.Code, rec.Body)
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected JSON response, got %q", ct)
+	}
+
+	if code == http.StatusOK {
+		return
+	}
+
+	var er errorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &er); err != nil {
+		t.Fatalf("Error decoding response: %s", err)
+	}
+
+	if er.Error == "" {
+		t.Error("Expected an error message")
+	}
+
+	if problems && len(er.Problems) == 0 {
+		t.Error("Expected the problems in the report to be listed")
+	} else if !problems && len(er.Problems) != 0 {
+		t.Errorf("Expected no problems, got %v", er.Problems)
+	}
+}
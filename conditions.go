@@ -0,0 +1,310 @@
+package taf
+
+import (
+	"slices"
+	"time"
+)
+
+// Conditions represents the conditions forecast at a specific time,
+// after all the changes in the forecast have been applied.
+type Conditions struct {
+	// Time is the time for which these conditions were resolved.
+	Time time.Time `json:"time,omitempty"`
+
+	// Visibility describes the prevailing visibility conditions.
+	Visibility Visibility `json:"visibility,omitempty"`
+
+	// Wind describes the prevailing wind conditions.
+	Wind Wind `json:"wind,omitempty"`
+
+	// SkyCondition lists the prevailing sky conditions.
+	SkyCondition []SkyCondition `json:"sky_condition,omitempty"`
+
+	// Weather lists the prevailing weather conditions.
+	Weather []Weather `json:"weather,omitempty"`
+
+	// Flags contains special flags associated with the prevailing conditions.
+	Flags []Flag `json:"flags,omitempty"`
+
+	// Alternates lists conditions that may occur instead of the
+	// prevailing conditions, such as TEMPO and PROB groups, as well
+	// as BECMG groups whose transition period is in progress.
+	Alternates []Alternate `json:"alternates,omitempty"`
+}
+
+// Alternate represents conditions that may occur instead of the
+// prevailing conditions. The groups that aren't changed by the
+// alternate are copied from the prevailing conditions.
+type Alternate struct {
+	// Type specifies the type of change that produced these conditions.
+	// For standalone PROB groups, this is empty.
+	Type ChangeType `json:"type,omitempty"`
+
+	// Probability indicates the percent chance of these conditions occurring.
+	// If the group didn't specify a probability, this is zero.
+	Probability int `json:"probability,omitempty"`
+
+	// Valid defines the period during which these conditions are applicable.
+	Valid ValidPair `json:"valid,omitempty"`
+
+	// Visibility describes the alternate visibility conditions.
+	Visibility Visibility `json:"visibility,omitempty"`
+
+	// Wind describes the alternate wind conditions.
+	Wind Wind `json:"wind,omitempty"`
+
+	// SkyCondition lists the alternate sky conditions.
+	SkyCondition []SkyCondition `json:"sky_condition,omitempty"`
+
+	// Weather lists the alternate weather conditions.
+	Weather []Weather `json:"weather,omitempty"`
+
+	// Flags contains special flags associated with the alternate conditions.
+	Flags []Flag `json:"flags,omitempty"`
+}
+
+// group contains the groups that can be changed by
+// FM, BECMG, TEMPO, and PROB groups.
+type group struct {
+	Visibility   Visibility
+	Wind         Wind
+	SkyCondition []SkyCondition
+	Weather      []Weather
+	Flags        []Flag
+}
+
+// pendingAlternate is an alternate that still needs to be
+// applied on top of the final prevailing conditions.
+type pendingAlternate struct {
+	typ   ChangeType
+	prob  int
+	valid ValidPair
+	group group
+}
+
+// At returns the conditions forecast at the given time.
+//
+// FM groups replace all the previous conditions once they start.
+// BECMG groups are applied once their transition period ends. During the
+// transition period, the new conditions are returned as an alternate, since
+// the change may happen at any time within it. TEMPO and PROB groups are
+// returned as alternates while they're valid.
+//
+// If t is outside the validity period of the forecast, only the Time
+// field of the returned conditions will be set.
+func (fc *Forecast) At(t time.Time) Conditions {
+	out := Conditions{Time: t}
+	if t.Before(fc.Valid.From) || !t.Before(fc.Valid.To) {
+		return out
+	}
+
+	prevailing := group{
+		Visibility:   fc.Visibility,
+		Wind:         fc.Wind,
+		SkyCondition: fc.SkyCondition,
+		Weather:      fc.Weather,
+		Flags:        fc.Flags,
+	}
+
+	var pending []pendingAlternate
+	for _, ch := range fc.Changes {
+		g := changeGroup(ch)
+
+		switch ch.Type {
+		case From:
+			if t.Before(ch.Valid.From) {
+				continue
+			}
+			// FM groups replace everything that came before,
+			// including any transitions that were in progress.
+			prevailing = g
+			pending = nil
+		case Becoming:
+			if t.Before(ch.Valid.From) {
+				continue
+			}
+
+			if !t.Before(ch.Valid.To) {
+				prevailing = overlay(prevailing, g)
+			} else {
+				pending = append(pending, pendingAlternate{Becoming, ch.Probability, ch.Valid, g})
+			}
+		default:
+			if ch.Valid.Contains(t) {
+				pending = append(pending, pendingAlternate{ch.Type, ch.Probability, ch.Valid, g})
+			}
+		}
+	}
+
+	for _, pr := range fc.Probabilities {
+		if pr.Valid.Contains(t) {
+			pending = append(pending, pendingAlternate{
+				prob:  pr.Value,
+				valid: pr.Valid,
+				group: group{
+					Visibility:   pr.Visibility,
+					Wind:         pr.Wind,
+					SkyCondition: pr.SkyCondition,
+					Weather:      pr.Weather,
+					Flags:        pr.Flags,
+				},
+			})
+		}
+	}
+
+	out.Visibility = prevailing.Visibility
+	out.Wind = prevailing.Wind
+	out.SkyCondition = slices.Clone(prevailing.SkyCondition)
+	out.Weather = slices.Clone(prevailing.Weather)
+	out.Flags = slices.Clone(prevailing.Flags)
+
+	for _, p := range pending {
+		g := overlay(prevailing, p.group)
+		out.Alternates = append(out.Alternates, Alternate{
+			Type:         p.typ,
+			Probability:  p.prob,
+			Valid:        p.valid,
+			Visibility:   g.Visibility,
+			Wind:         g.Wind,
+			SkyCondition: g.SkyCondition,
+			Weather:      g.Weather,
+			Flags:        g.Flags,
+		})
+	}
+
+	return out
+}
+
+// Timeline returns the conditions forecast at every step within the
+// validity period of the forecast, starting at the beginning of the
+// period. If step isn't positive, a step of one hour is used.
+func (fc *Forecast) Timeline(step time.Duration) []Conditions {
+	if step <= 0 {
+		step = time.Hour
+	}
+
+	var out []Conditions
+	for t := fc.Valid.From; t.Before(fc.Valid.To); t = t.Add(step) {
+		out = append(out, fc.At(t))
+	}
+	return out
+}
+
+// groupBoundaries returns start, followed by the start and end times of
+// every change and probability group in the given forecasts that are
+// between start and end, in chronological order without duplicates.
+func groupBoundaries(start, end time.Time, fcs ...*Forecast) []time.Time {
+	out := []time.Time{start}
+	add := func(vp ValidPair) {
+		for _, t := range []time.Time{vp.From, vp.To} {
+			if t.After(start) && t.Before(end) {
+				out = append(out, t)
+			}
+		}
+	}
+
+	for _, fc := range fcs {
+		for _, ch := range fc.Changes {
+			add(ch.Valid)
+		}
+		for _, pr := range fc.Probabilities {
+			add(pr.Valid)
+		}
+	}
+
+	slices.SortFunc(out, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(out, time.Time.Equal)
+}
+
+// Contains checks whether t is within the valid pair. The start
+// of the period is inclusive, and the end is exclusive. If the pair
+// has no end time, only the start time is checked.
+func (vp ValidPair) Contains(t time.Time) bool {
+	if t.Before(vp.From) {
+		return false
+	}
+	return vp.To.IsZero() || t.Before(vp.To)
+}
+
+// changeGroup returns the groups within a change
+func changeGroup(ch *Change) group {
+	return group{
+		Visibility:   ch.Visibility,
+		Wind:         ch.Wind,
+		SkyCondition: ch.SkyCondition,
+		Weather:      ch.Weather,
+		Flags:        ch.Flags,
+	}
+}
+
+// overlay returns a copy of base with the groups that
+// are present in g replacing the ones in base.
+func overlay(base, g group) group {
+	out := group{
+		Visibility:   base.Visibility,
+		Wind:         base.Wind,
+		SkyCondition: slices.Clone(base.SkyCondition),
+		Weather:      slices.Clone(base.Weather),
+		Flags:        slices.Clone(base.Flags),
+	}
+
+	if g.Wind.Speed.Unit != "" {
+		out.Wind = g.Wind
+	}
+
+	if slices.Contains(g.Flags, CeilingAndVisibilityOK) {
+		// CAVOK replaces the visibility, weather, and sky conditions
+		out.Visibility = Visibility{}
+		out.Weather = nil
+		out.SkyCondition = nil
+		out.Flags = slices.Clone(g.Flags)
+		return out
+	}
+
+	changed := false
+
+	if g.Visibility.Unit != "" {
+		out.Visibility = g.Visibility
+		changed = true
+	}
+
+	if len(g.SkyCondition) > 0 {
+		out.SkyCondition = slices.Clone(g.SkyCondition)
+		out.Flags = removeFlags(out.Flags, NoSignificantCloud, NoCloudDetected)
+		changed = true
+	} else if slices.Contains(g.Flags, NoSignificantCloud) || slices.Contains(g.Flags, NoCloudDetected) {
+		// NSC and NCD replace any previously forecast clouds
+		out.SkyCondition = nil
+		out.Flags = removeFlags(out.Flags, NoSignificantCloud, NoCloudDetected)
+	}
+
+	if len(g.Weather) > 0 {
+		out.Weather = slices.Clone(g.Weather)
+		out.Flags = removeFlags(out.Flags, NoSignificantWeather)
+		changed = true
+	} else if slices.Contains(g.Flags, NoSignificantWeather) {
+		// NSW indicates that previously forecast weather ends
+		out.Weather = nil
+	}
+
+	// If any of the groups implied by CAVOK changed,
+	// CAVOK no longer applies.
+	if changed {
+		out.Flags = removeFlags(out.Flags, CeilingAndVisibilityOK)
+	}
+
+	for _, f := range g.Flags {
+		if !slices.Contains(out.Flags, f) {
+			out.Flags = append(out.Flags, f)
+		}
+	}
+
+	return out
+}
+
+// removeFlags returns flags with every occurrence of the given flags removed
+func removeFlags(flags []Flag, remove ...Flag) []Flag {
+	return slices.DeleteFunc(flags, func(f Flag) bool {
+		return slices.Contains(remove, f)
+	})
+}
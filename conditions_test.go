@@ -0,0 +1,169 @@
+package taf
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"go.elara.ws/taf/units"
+)
+
+func TestAt(t *testing.T) {
+	const data = `TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040
+  BECMG 2201/2204 BKN007
+  PROB30
+  TEMPO 2202/2206 8000 BKN004
+  FM221200 24010KT CAVOK`
+
+	fc, err := DecodeWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	wind := Wind{Direction: Direction{Value: 220}, Speed: units.Velocity{Value: 8, Unit: units.Knots}}
+	vis := Visibility{Length: units.Length{Value: 9999, Unit: units.Meters}}
+
+	becmg := fc.Changes[0]
+	tempo := fc.Changes[1]
+
+	testCases := []struct {
+		name     string
+		time     time.Time
+		expected Conditions
+	}{
+		{
+			name: "before",
+			time: time.Date(2023, time.August, 22, 0, 0, 0, 0, time.UTC),
+			expected: Conditions{
+				Wind:         wind,
+				Visibility:   vis,
+				SkyCondition: []SkyCondition{{Type: Few, Altitude: units.HeightFeet(4000)}},
+			},
+		},
+		{
+			name: "during",
+			time: time.Date(2023, time.August, 22, 2, 30, 0, 0, time.UTC),
+			expected: Conditions{
+				Wind:         wind,
+				Visibility:   vis,
+				SkyCondition: []SkyCondition{{Type: Few, Altitude: units.HeightFeet(4000)}},
+				Alternates: []Alternate{
+					{
+						Type:         Becoming,
+						Valid:        becmg.Valid,
+						Wind:         wind,
+						Visibility:   vis,
+						SkyCondition: []SkyCondition{{Type: Broken, Altitude: units.HeightFeet(700)}},
+					},
+					{
+						Type:         Temporary,
+						Probability:  30,
+						Valid:        tempo.Valid,
+						Wind:         wind,
+						Visibility:   Visibility{Length: units.Length{Value: 8000, Unit: units.Meters}},
+						SkyCondition: []SkyCondition{{Type: Broken, Altitude: units.HeightFeet(400)}},
+					},
+				},
+			},
+		},
+		{
+			name: "after",
+			time: time.Date(2023, time.August, 22, 7, 0, 0, 0, time.UTC),
+			expected: Conditions{
+				Wind:         wind,
+				Visibility:   vis,
+				SkyCondition: []SkyCondition{{Type: Broken, Altitude: units.HeightFeet(700)}},
+			},
+		},
+		{
+			name: "from",
+			time: time.Date(2023, time.August, 22, 12, 0, 0, 0, time.UTC),
+			expected: Conditions{
+				Wind:  Wind{Direction: Direction{Value: 240}, Speed: units.Velocity{Value: 10, Unit: units.Knots}},
+				Flags: []Flag{CeilingAndVisibilityOK},
+			},
+		},
+		{
+			name:     "outside",
+			time:     time.Date(2023, time.August, 23, 0, 0, 0, 0, time.UTC),
+			expected: Conditions{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.expected.Time = tc.time
+			if diff := deep.Equal(fc.At(tc.time), tc.expected); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+
+	timeline := fc.Timeline(time.Hour)
+	if len(timeline) != 30 {
+		t.Errorf("Expected 30 timeline entries, got %d", len(timeline))
+	}
+}
+
+func TestNoSignificantWeather(t *testing.T) {
+	const data = `TAF AMD RJTT 212300Z 2200/2306 18010KT 4000 -RA BKN010
+  BECMG 2203/2205 9999 NSW NSC`
+
+	fc, err := DecodeWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	if fc.String() != data {
+		t.Errorf("Expected encoded forecast to match input, got %q", fc.String())
+	}
+
+	cond := fc.At(time.Date(2023, time.August, 22, 6, 0, 0, 0, time.UTC))
+	if len(cond.Weather) != 0 {
+		t.Errorf("Expected NSW to clear the weather, got %v", cond.Weather)
+	}
+
+	if len(cond.SkyCondition) != 0 {
+		t.Errorf("Expected NSC to clear the sky conditions, got %v", cond.SkyCondition)
+	}
+
+	if diff := deep.Equal(cond.Flags, []Flag{NoSignificantWeather, NoSignificantCloud}); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestCancelled(t *testing.T) {
+	const data = "TAF AMD EDDF 211030Z 2112/2218 CNL"
+
+	fc, err := DecodeWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	if diff := deep.Equal(fc.Flags, []Flag{Cancelled}); diff != nil {
+		t.Error(diff)
+	}
+
+	if fc.String() != data {
+		t.Errorf("Expected encoded forecast to match input, got %q", fc.String())
+	}
+
+	fc, err = DecodeString("EDDF 211030Z NIL")
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	if diff := deep.Equal(fc.Flags, []Flag{Missing}); diff != nil {
+		t.Error(diff)
+	}
+}
@@ -25,6 +25,8 @@ func convertSkyConditionType(s string) SkyConditionType {
 		return VerticalVisibility
 	case "SKC":
 		return SkyClear
+	case "CLR":
+		return Clear
 	default:
 		return ""
 	}
@@ -58,7 +60,7 @@ func convertDescriptor(s string) Descriptor {
 		return Shallow
 	case "BC":
 		return Patches
-	case "DC":
+	case "DR":
 		return LowDrifting
 	case "BL":
 		return Blowing
@@ -141,8 +143,9 @@ func convertPhenomenon(s string) Phenomenon {
 }
 
 func convertTemperatureType(s string) TemperatureType {
+	// US military TAFs use T for the maximum temperature
 	switch s {
-	case "TX":
+	case "TX", "T":
 		return High
 	case "TN":
 		return Low
@@ -163,3 +166,180 @@ func convertChangeType(s string) ChangeType {
 		return ""
 	}
 }
+
+func convertRunwayDeposit(s string) RunwayDeposit {
+	switch s {
+	case "0":
+		return ClearAndDry
+	case "1":
+		return Damp
+	case "2":
+		return WetOrPatches
+	case "3":
+		return RimeOrFrost
+	case "4":
+		return DrySnow
+	case "5":
+		return WetSnow
+	case "6":
+		return Slush
+	case "7":
+		return Ice
+	case "8":
+		return CompactedSnow
+	case "9":
+		return FrozenRuts
+	default:
+		return ""
+	}
+}
+
+func convertBrakingAction(code int) BrakingAction {
+	switch code {
+	case 91:
+		return BrakingPoor
+	case 92:
+		return BrakingMediumPoor
+	case 93:
+		return BrakingMedium
+	case 94:
+		return BrakingMediumGood
+	case 95:
+		return BrakingGood
+	case 99:
+		return BrakingUnreliable
+	default:
+		return ""
+	}
+}
+
+func encodeReportType(rt ReportType) string {
+	switch rt {
+	case Amended:
+		return "AMD"
+	case Corrected:
+		return "COR"
+	default:
+		return ""
+	}
+}
+
+func encodeSkyConditionType(t SkyConditionType) string {
+	switch t {
+	case Few:
+		return "FEW"
+	case Scattered:
+		return "SCT"
+	case Broken:
+		return "BKN"
+	case Overcast:
+		return "OVC"
+	case VerticalVisibility:
+		return "VV"
+	case SkyClear:
+		return "SKC"
+	case Clear:
+		return "CLR"
+	default:
+		return ""
+	}
+}
+
+func encodeCloudType(ct CloudType) string {
+	switch ct {
+	case CumuloNimbus:
+		return "CB"
+	case ToweringCumulus:
+		return "TCU"
+	default:
+		return ""
+	}
+}
+
+func encodeDescriptor(d Descriptor) string {
+	switch d {
+	case Shallow:
+		return "MI"
+	case Patches:
+		return "BC"
+	case LowDrifting:
+		return "DR"
+	case Blowing:
+		return "BL"
+	case Showers:
+		return "SH"
+	case Thunderstorm:
+		return "TS"
+	case Freezing:
+		return "FZ"
+	case Partial:
+		return "PR"
+	default:
+		return ""
+	}
+}
+
+func encodePrecipitation(p Precipitation) string {
+	switch p {
+	case Drizzle:
+		return "DZ"
+	case Rain:
+		return "RA"
+	case Snow:
+		return "SN"
+	case SnowGrains:
+		return "SG"
+	case IceCrystals:
+		return "IC"
+	case IcePellets:
+		return "PL"
+	case Hail:
+		return "GR"
+	case SmallHail:
+		return "GS"
+	case Unknown:
+		return "UP"
+	default:
+		return ""
+	}
+}
+
+func encodeObscuration(o Obscuration) string {
+	switch o {
+	case Mist:
+		return "BR"
+	case Fog:
+		return "FG"
+	case Smoke:
+		return "FU"
+	case Dust:
+		return "DU"
+	case Sand:
+		return "SA"
+	case Haze:
+		return "HZ"
+	case Spray:
+		return "PY"
+	case VolcanicAsh:
+		return "VA"
+	default:
+		return ""
+	}
+}
+
+func encodePhenomenon(p Phenomenon) string {
+	switch p {
+	case Whirls:
+		return "PO"
+	case Squalls:
+		return "SQ"
+	case FunnelCloud:
+		return "FC"
+	case Sandstorm:
+		return "SS"
+	case Duststorm:
+		return "DS"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,288 @@
+package taf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Decoder decodes a stream containing multiple TAF reports, such as
+// the bulk files provided by aviationweather.gov. Reports are separated
+// by a line starting with a "TAF" header, a blank line, or an "=" character.
+type Decoder struct {
+	s    *bufio.Scanner
+	opts Options
+
+	// pending holds text that belongs to the next report, such as
+	// a line starting with a TAF header or the text after an "=".
+	pending    string
+	hasPending bool
+	// pendingPrefix is the text before the pending text on its line
+	pendingPrefix string
+
+	// read is the number of bytes read from the stream
+	read int
+	// line is the number of the last line that was read
+	line int
+	// lineOffset is the byte offset of the last line that was read
+	lineOffset int
+	// prefix is the text before the text returned by nextLine on its line
+	prefix string
+
+	// start is the line on which the last decoded report started
+	start int
+	// lineShifts contains the number of bytes to add to an offset within
+	// each line of the last decoded report to get an offset within the
+	// stream. It's kept per line, since the stream's line endings may be
+	// longer than the "\n" that separates the lines of the report.
+	lineShifts []int
+	// startColumn is the number of characters before the last decoded
+	// report on the line on which it started
+	startColumn int
+}
+
+// NewDecoder creates a new Decoder that reads from r using
+// default options.
+func NewDecoder(r io.Reader) *Decoder {
+	return NewDecoderWithOptions(r, Options{})
+}
+
+// NewDecoderWithOptions creates a new Decoder that reads from r
+// using the given options.
+func NewDecoderWithOptions(r io.Reader, opts Options) *Decoder {
+	d := &Decoder{s: bufio.NewScanner(r), opts: opts}
+	d.s.Split(d.scanLines)
+	return d
+}
+
+// Next decodes the next report in the stream. If the report fails to
+// decode, the error is returned and the next call to Next will continue
+// with the following report. When there are no more reports, Next
+// returns io.EOF. The positions of spans and problems are relative to
+// the stream rather than the report.
+func (d *Decoder) Next() (*Forecast, error) {
+	report, err := d.nextReport()
+	if err != nil {
+		return nil, err
+	}
+
+	fc, err := DecodeWithOptions(strings.NewReader(report), d.opts)
+	if err != nil {
+		// Make the positions of the problems relative to the
+		// stream rather than the report.
+		var derr *DecodeError
+		if errors.As(err, &derr) {
+			d.shiftProblems(derr.Problems)
+			return nil, derr
+		}
+		return nil, fmt.Errorf("line %d: %w", d.start, err)
+	}
+
+	d.shiftProblems(fc.Warnings)
+	d.shiftSpans(fc.Spans)
+	for _, ch := range fc.Changes {
+		d.shiftSpans(ch.Spans)
+	}
+	for _, pr := range fc.Probabilities {
+		d.shiftSpans(pr.Spans)
+	}
+	fc.Line = d.start
+	return fc, nil
+}
+
+// shiftProblems makes the positions of the given problems
+// relative to the stream rather than the current report.
+func (d *Decoder) shiftProblems(ps []Problem) {
+	for i := range ps {
+		d.shift(&ps[i].Offset, &ps[i].Line, &ps[i].Column)
+	}
+}
+
+// shiftSpans makes the positions of the given spans
+// relative to the stream rather than the current report.
+func (d *Decoder) shiftSpans(spans []Span) {
+	for i := range spans {
+		d.shift(&spans[i].Offset, &spans[i].Line, &spans[i].Column)
+	}
+}
+
+// shift converts a position within the current report
+// to a position within the stream
+func (d *Decoder) shift(offset, line, column *int) {
+	if *line == 1 {
+		*column += d.startColumn
+	}
+	if *line >= 1 && *line <= len(d.lineShifts) {
+		*offset += d.lineShifts[*line-1]
+	}
+	*line += d.start - 1
+}
+
+// Line returns the line number, starting at 1, on which the
+// report most recently returned by Next started.
+func (d *Decoder) Line() int {
+	return d.start
+}
+
+// nextReport returns the text of the next report in the stream
+func (d *Decoder) nextReport() (string, error) {
+	sb := &strings.Builder{}
+	// lead is the length of the leading whitespace of the report
+	lead := 0
+
+	for {
+		line, ok := d.nextLine()
+		if !ok {
+			break
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			if sb.Len() > 0 {
+				break
+			}
+			continue
+		}
+
+		// A line starting with a TAF header begins a new report
+		if sb.Len() > 0 && isHeader(trimmed) {
+			d.pending, d.hasPending = line, true
+			d.pendingPrefix = d.prefix
+			break
+		}
+
+		// The leading whitespace of the report is trimmed below, so
+		// it's counted as part of the text before the report.
+		if sb.Len() == 0 {
+			lead = len(line) - len(strings.TrimLeftFunc(line, unicode.IsSpace))
+			before := d.prefix + line[:lead]
+			d.start = d.line
+			d.startColumn = utf8.RuneCountInString(before)
+			d.lineShifts = append(d.lineShifts[:0], d.lineOffset+len(before))
+		} else {
+			d.lineShifts = append(d.lineShifts, d.lineOffset-(sb.Len()-lead))
+		}
+
+		// An "=" ends the report. Anything after it is part of the next one.
+		// Lines aren't trimmed, so that the indentation of the groups
+		// after the first line is kept.
+		before, after, found := strings.Cut(line, "=")
+		sb.WriteString(before)
+		sb.WriteByte('\n')
+		if found {
+			if strings.TrimSpace(after) != "" {
+				d.pending, d.hasPending = after, true
+				d.pendingPrefix = d.prefix + before + "="
+			}
+			break
+		}
+	}
+
+	if err := d.s.Err(); err != nil {
+		return "", err
+	}
+
+	report := strings.TrimSpace(sb.String())
+	if report == "" {
+		return "", io.EOF
+	}
+	return report, nil
+}
+
+// nextLine returns the pending text if there is any, otherwise
+// it reads the next line from the underlying reader.
+func (d *Decoder) nextLine() (string, bool) {
+	if d.hasPending {
+		d.hasPending = false
+		d.prefix = d.pendingPrefix
+		return d.pending, true
+	}
+
+	d.lineOffset = d.read
+	if !d.s.Scan() {
+		return "", false
+	}
+	d.line++
+	d.prefix = ""
+	return d.s.Text(), true
+}
+
+// scanLines splits the stream into lines like bufio.ScanLines,
+// while keeping track of the number of bytes that were read.
+func (d *Decoder) scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	advance, token, err := bufio.ScanLines(data, atEOF)
+	d.read += advance
+	return advance, token, err
+}
+
+// splitReports returns the text of each report in r
+func splitReports(r io.Reader) ([]string, error) {
+	d := NewDecoder(r)
+
+	var out []string
+	for {
+		report, err := d.nextReport()
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		} else if err != nil {
+			return out, err
+		}
+		out = append(out, report)
+	}
+}
+
+// reportIdentifier returns the ICAO identifier in the text of a report
+func reportIdentifier(report string) string {
+	for _, field := range strings.Fields(report) {
+		switch field {
+		case "TAF", "AMD", "COR":
+			continue
+		}
+		return field
+	}
+	return ""
+}
+
+// isHeader checks whether a line starts with a TAF header
+func isHeader(s string) bool {
+	return s == "TAF" || strings.HasPrefix(s, "TAF ")
+}
+
+// DecodeAll decodes every report in a reader using default options.
+// This is equivalent to DecodeAllWithOptions(r, Options{}).
+func DecodeAll(r io.Reader) ([]*Forecast, error) {
+	return DecodeAllWithOptions(r, Options{})
+}
+
+// DecodeAllWithOptions decodes every report in a reader. Reports that
+// fail to decode are skipped, and their errors are joined together
+// and returned along with the reports that were decoded successfully.
+func DecodeAllWithOptions(r io.Reader, opts Options) ([]*Forecast, error) {
+	d := NewDecoderWithOptions(r, opts)
+
+	var (
+		out  []*Forecast
+		errs []error
+	)
+
+	for {
+		fc, err := d.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			// If the underlying reader failed, there's nothing more to decode
+			if d.s.Err() != nil {
+				return out, errors.Join(append(errs, err)...)
+			}
+			errs = append(errs, err)
+			continue
+		}
+		out = append(out, fc)
+	}
+
+	return out, errors.Join(errs...)
+}
@@ -0,0 +1,192 @@
+package taf
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func TestDecoder(t *testing.T) {
+	const data = `TAF KJFK 212335Z 2200/2306 33012G18KT P6SM FEW060 BKN250
+  FM220300 36014KT P6SM FEW060 SCT150
+TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040
+  BECMG 2201/2204 BKN007=
+
+LFBD 211700Z 2118/2224 31010KT ?????
+
+UUEE 211958Z 2121/2221 VRB01MPS 9999 SCT030= ZGSZ 211907Z 2118/2218 18004MPS 8000 SCT020`
+
+	d := NewDecoderWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+	})
+
+	expected := []struct {
+		id   string
+		line int
+		err  bool
+	}{
+		{"KJFK", 1, false},
+		{"EGLL", 3, false},
+		{"", 6, true},
+		{"UUEE", 8, false},
+		{"ZGSZ", 8, false},
+	}
+
+	for _, e := range expected {
+		fc, err := d.Next()
+		if e.err {
+			if err == nil {
+				t.Errorf("Expected error for report on line %d", e.line)
+			}
+		} else if err != nil {
+			t.Fatalf("Error during parsing: %s", err)
+		} else if fc.Identifier != e.id {
+			t.Errorf("Expected identifier %q, got %q", e.id, fc.Identifier)
+		} else if fc.Line != e.line {
+			t.Errorf("Expected forecast %q to start on line %d, got %d", e.id, e.line, fc.Line)
+		}
+
+		if d.Line() != e.line {
+			t.Errorf("Expected report %q to start on line %d, got %d", e.id, e.line, d.Line())
+		}
+	}
+
+	if _, err := d.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+
+	reports, err := splitReports(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("Error splitting reports: %s", err)
+	}
+
+	if reports[1] != "TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040\n  BECMG 2201/2204 BKN007" {
+		t.Errorf("Expected indentation to be kept, got %q", reports[1])
+	}
+
+	fcs, err := DecodeAll(strings.NewReader(data))
+	if err == nil {
+		t.Error("Expected error from DecodeAll")
+	}
+
+	if len(fcs) != 4 {
+		t.Errorf("Expected 4 forecasts, got %d", len(fcs))
+	}
+}
+
+func TestDecoderSpans(t *testing.T) {
+	const data = `TAF KJFK 212335Z 2200/2306 33012G18KT P6SM FEW060 BKN250
+  FM220300 36014KT P6SM FEW060 SCT150
+
+  TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040
+  BECMG 2201/2204 BKN007
+  PROB30
+  TEMPO 2202/2206 8000 BKN004= UUEE 211958Z 2121/2221 VRB01MPS 9999 SCT030`
+
+	d := NewDecoderWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+		Spans: true,
+	})
+
+	expected := []struct {
+		line  int
+		ident Span
+		raw   string
+	}{
+		{1, Span{Field: "identifier", Offset: 4, Line: 1, Column: 5, Token: "KJFK"}, "TAF KJFK 212335Z 2200/2306 33012G18KT P6SM FEW060 BKN250\n  FM220300 36014KT P6SM FEW060 SCT150"},
+		{4, Span{Field: "identifier", Offset: 102, Line: 4, Column: 7, Token: "EGLL"}, "TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040\n  BECMG 2201/2204 BKN007\n  PROB30\n  TEMPO 2202/2206 8000 BKN004"},
+		{7, Span{Field: "identifier", Offset: 210, Line: 7, Column: 32, Token: "UUEE"}, "UUEE 211958Z 2121/2221 VRB01MPS 9999 SCT030"},
+	}
+
+	var fcs []*Forecast
+	for _, e := range expected {
+		fc, err := d.Next()
+		if err != nil {
+			t.Fatalf("Error during parsing: %s", err)
+		}
+		fcs = append(fcs, fc)
+
+		if fc.Line != e.line {
+			t.Errorf("Expected forecast %q to start on line %d, got %d", fc.Identifier, e.line, fc.Line)
+		}
+
+		if fc.Raw != e.raw {
+			t.Errorf("Unexpected raw text: %q", fc.Raw)
+		}
+
+		if diff := deep.Equal(fc.Spans[0], e.ident); diff != nil {
+			t.Error(diff)
+		}
+
+		checkStreamSpans(t, data, fc)
+	}
+
+	// The TEMPO group is on the fourth line of the second report,
+	// which is the seventh line of the stream.
+	tempo := Span{Field: "type", Offset: 181, Line: 7, Column: 3, Token: "TEMPO 2202/2206"}
+	if diff := deep.Equal(fcs[1].Changes[1].Spans[1], tempo); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestDecoderSpansCRLF(t *testing.T) {
+	const data = "TAF KJFK 212335Z 2200/2306 33012G18KT P6SM FEW060 BKN250\r\n" +
+		"  FM220300 36014KT P6SM FEW060 SCT150\r\n" +
+		"  PROB30 TEMPO 2203/2206 3SM BR\r\n" +
+		"\r\n" +
+		"TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040\r\n" +
+		"  BECMG 2201/2204 BKN007=\r\n"
+
+	d := NewDecoderWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+		Spans: true,
+	})
+
+	for _, id := range []string{"KJFK", "EGLL"} {
+		fc, err := d.Next()
+		if err != nil {
+			t.Fatalf("Error during parsing: %s", err)
+		}
+
+		if fc.Identifier != id {
+			t.Errorf("Expected identifier %q, got %q", id, fc.Identifier)
+		}
+
+		checkStreamSpans(t, data, fc)
+	}
+}
+
+// checkStreamSpans checks that every span in fc points
+// to its token in the stream the forecast was decoded from
+func checkStreamSpans(t *testing.T, data string, fc *Forecast) {
+	t.Helper()
+
+	spans := fc.Spans
+	for _, ch := range fc.Changes {
+		spans = append(spans, ch.Spans...)
+	}
+	for _, pr := range fc.Probabilities {
+		spans = append(spans, pr.Spans...)
+	}
+
+	for _, span := range spans {
+		end := span.Offset + len(span.Token)
+		if end > len(data) || data[span.Offset:end] != span.Token {
+			t.Errorf("Span %q at offset %d doesn't match the stream", span.Token, span.Offset)
+			continue
+		}
+
+		before := data[:span.Offset]
+		line := strings.Count(before, "\n") + 1
+		column := span.Offset - strings.LastIndexByte(before, '\n')
+		if span.Line != line || span.Column != column {
+			t.Errorf("Expected span %q at %d:%d, got %d:%d", span.Token, line, column, span.Line, span.Column)
+		}
+	}
+}
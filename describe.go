@@ -0,0 +1,523 @@
+package taf
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.elara.ws/taf/units"
+)
+
+// DescribeOptions contains options for Describe.
+type DescribeOptions struct {
+	// LocalTime specifies that times should be shown in the
+	// airport's local timezone rather than in UTC. If the airport's
+	// timezone isn't known, UTC is used.
+	LocalTime bool
+}
+
+// Describe returns a plain-language description of the forecast, with
+// one line for the base forecast and one for each change or probability.
+func (fc *Forecast) Describe(opts DescribeOptions) string {
+	d := describer{loc: time.UTC}
+	if opts.LocalTime && fc.Airport.Timezone != "" {
+		if loc, err := time.LoadLocation(fc.Airport.Timezone); err == nil {
+			d.loc = loc
+		}
+	}
+
+	var lines []string
+
+	header := "Forecast"
+	switch fc.ReportType {
+	case Amended:
+		header = "Amended forecast"
+	case Corrected:
+		header = "Corrected forecast"
+	}
+
+	if fc.Identifier != "" {
+		header += " for " + fc.Identifier
+		if fc.Airport.Name != "" {
+			header += " (" + fc.Airport.Name + ")"
+		}
+	}
+
+	if !fc.PublishTime.IsZero() {
+		header += ", issued " + d.time(fc.PublishTime)
+	}
+
+	if !fc.Valid.From.IsZero() {
+		header += ", valid " + d.period(fc.Valid)
+	}
+	lines = append(lines, header+".")
+
+	switch {
+	case slices.Contains(fc.Flags, Missing):
+		lines = append(lines, "The forecast is missing.")
+	case slices.Contains(fc.Flags, Cancelled):
+		lines = append(lines, "The forecast has been cancelled.")
+	}
+
+	if s := d.conditions(conditionGroups{
+		Wind:         fc.Wind,
+		WindShear:    fc.WindShear,
+		Visibility:   fc.Visibility,
+		Weather:      fc.Weather,
+		SkyCondition: fc.SkyCondition,
+		Flags:        fc.Flags,
+		Icing:        fc.Icing,
+		Turbulence:   fc.Turbulence,
+		Altimeter:    fc.Altimeter,
+		Temperature:  fc.Temperature,
+	}); s != "" {
+		lines = append(lines, sentence(s))
+	}
+
+	fc.forEachGroup(func(ch *Change) {
+		lines = append(lines, d.change(ch))
+	}, func(pr *Probability) {
+		lines = append(lines, d.probability(pr))
+	})
+
+	if fc.Remark != "" {
+		lines = append(lines, "Remarks: "+fc.Remark)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// describer holds the state needed to describe a forecast
+type describer struct {
+	loc *time.Location
+}
+
+// change describes a change group
+func (d describer) change(ch *Change) string {
+	var prefix string
+	switch ch.Type {
+	case From:
+		prefix = "from " + d.time(ch.Valid.From)
+	case Becoming:
+		prefix = "gradually becoming " + d.period(ch.Valid)
+	case Temporary:
+		prefix = "temporarily " + d.period(ch.Valid)
+	}
+
+	if ch.Probability != 0 {
+		prefix = fmt.Sprintf("%d%% chance, %s", ch.Probability, prefix)
+	}
+
+	return sentence(prefix + ": " + d.conditions(conditionGroups{
+		Wind:         ch.Wind,
+		WindShear:    ch.WindShear,
+		Visibility:   ch.Visibility,
+		Weather:      ch.Weather,
+		SkyCondition: ch.SkyCondition,
+		Flags:        ch.Flags,
+		Icing:        ch.Icing,
+		Turbulence:   ch.Turbulence,
+		Altimeter:    ch.Altimeter,
+		Temperature:  ch.Temperature,
+	}))
+}
+
+// probability describes a probability group
+func (d describer) probability(pr *Probability) string {
+	prefix := fmt.Sprintf("%d%% chance %s", pr.Value, d.period(pr.Valid))
+	return sentence(prefix + ": " + d.conditions(conditionGroups{
+		Wind:         pr.Wind,
+		WindShear:    pr.WindShear,
+		Visibility:   pr.Visibility,
+		Weather:      pr.Weather,
+		SkyCondition: pr.SkyCondition,
+		Flags:        pr.Flags,
+		Icing:        pr.Icing,
+		Turbulence:   pr.Turbulence,
+		Altimeter:    pr.Altimeter,
+		Temperature:  pr.Temperature,
+	}))
+}
+
+// conditions describes the groups shared by forecasts,
+// changes, and probabilities as a list of clauses.
+func (d describer) conditions(c conditionGroups) string {
+	var out []string
+
+	if c.Wind.Speed.Unit != "" {
+		out = append(out, describeWind(c.Wind))
+	}
+
+	if slices.Contains(c.Flags, CeilingAndVisibilityOK) {
+		out = append(out, "ceiling and visibility OK")
+	}
+
+	if c.Visibility.Unit != "" {
+		out = append(out, describeVisibility(c.Visibility))
+	}
+
+	for _, w := range c.Weather {
+		out = append(out, describeWeather(w))
+	}
+	if slices.Contains(c.Flags, NoSignificantWeather) {
+		out = append(out, "no significant weather")
+	}
+
+	for _, sc := range c.SkyCondition {
+		out = append(out, describeSkyCondition(sc))
+	}
+	if slices.Contains(c.Flags, NoSignificantCloud) {
+		out = append(out, "no significant clouds")
+	}
+	if slices.Contains(c.Flags, NoCloudDetected) {
+		out = append(out, "no clouds detected")
+	}
+
+	if c.WindShear.Speed.Unit != "" {
+		out = append(out, fmt.Sprintf("wind shear at %s ft with %s", formatFeet(c.WindShear.WindShear), describeWind(c.WindShear)))
+	}
+
+	for _, ic := range c.Icing {
+		out = append(out, describeLayer(describeIcing(ic), ic.Base, ic.Thickness))
+	}
+
+	for _, tb := range c.Turbulence {
+		out = append(out, describeLayer(describeTurbulence(tb), tb.Base, tb.Thickness))
+	}
+
+	if c.Altimeter.Unit != "" {
+		out = append(out, "lowest altimeter setting "+describeAltimeter(c.Altimeter))
+	}
+
+	for _, t := range c.Temperature {
+		out = append(out, d.temperature(t))
+	}
+
+	if len(out) == 0 {
+		return "no change"
+	}
+	return strings.Join(out, ", ")
+}
+
+// temperature describes a temperature group
+func (d describer) temperature(t Temperature) string {
+	kind := "temperature"
+	switch t.Type {
+	case High:
+		kind = "maximum temperature"
+	case Low:
+		kind = "minimum temperature"
+	}
+	unit := t.Unit
+	if unit == "" {
+		unit = units.Celsius
+	}
+	return fmt.Sprintf("%s %d%s at %s", kind, t.Value, unit.Symbol(), d.time(t.Time))
+}
+
+// period describes a validity period
+func (d describer) period(vp ValidPair) string {
+	if vp.To.IsZero() {
+		return "from " + d.time(vp.From)
+	}
+	return "from " + d.time(vp.From) + " to " + d.time(vp.To)
+}
+
+// time formats a time in the describer's location
+func (d describer) time(t time.Time) string {
+	if d.loc == time.UTC {
+		return t.UTC().Format("Jan 2 15:04Z")
+	}
+	return t.In(d.loc).Format("Jan 2 15:04 MST")
+}
+
+// describeWind describes a wind group. It returns an empty
+// string if there's no wind group.
+func describeWind(w Wind) string {
+	if w.Speed.Unit == "" {
+		return ""
+	}
+
+	unit := describeSpeedUnit(w.Speed.Unit)
+	if w.Speed.Value == 0 && w.Gusts.Value == 0 {
+		return "calm wind"
+	}
+
+	var out string
+	if w.Direction.Variable {
+		out = fmt.Sprintf("variable wind at %g %s", w.Speed.Value, unit)
+	} else {
+		out = fmt.Sprintf("wind %03d° at %g %s", w.Direction.Value, w.Speed.Value, unit)
+	}
+
+	if w.Gusts.Value != 0 {
+		out += fmt.Sprintf(" gusting to %g %s", w.Gusts.In(w.Speed.Unit), unit)
+	}
+
+	return out
+}
+
+func describeVisibility(v Visibility) string {
+	unit := describeDistanceUnit(v.Unit, v.Value <= 1)
+	val := strconv.FormatFloat(v.Value, 'f', -1, 64)
+	if v.Unit == units.Miles {
+		val = formatMixedNumber(v.Value)
+	}
+
+	switch {
+	case v.Plus:
+		return fmt.Sprintf("visibility more than %s %s", val, unit)
+	case v.Minus:
+		return fmt.Sprintf("visibility less than %s %s", val, unit)
+	case v.Unit == units.Meters && v.Value >= 9999:
+		// 9999 means 10 km or more
+		return "visibility 10 kilometers or more"
+	default:
+		return fmt.Sprintf("visibility %s %s", val, unit)
+	}
+}
+
+func describeWeather(w Weather) string {
+	var words []string
+
+	switch w.Modifier {
+	case Heavy:
+		words = append(words, "heavy")
+	case Light:
+		words = append(words, "light")
+	}
+
+	phenomena := describePhenomena(w)
+
+	switch w.Descriptor {
+	case Showers:
+		if phenomena == "" {
+			words = append(words, "showers")
+		} else {
+			words = append(words, phenomena, "showers")
+		}
+	case Thunderstorm:
+		words = append(words, "thunderstorms")
+		if phenomena != "" {
+			words = append(words, "with", phenomena)
+		}
+	case Patches:
+		words = append(words, "patches of", phenomena)
+	case "":
+		words = append(words, phenomena)
+	default:
+		words = append(words, descriptorNames[w.Descriptor], phenomena)
+	}
+
+	if w.Vicinity {
+		words = append(words, "in the vicinity")
+	}
+
+	return strings.Join(words, " ")
+}
+
+// describePhenomena returns the names of the precipitation,
+// obscuration, and other phenomena in a weather group
+func describePhenomena(w Weather) string {
+	var names []string
+	if w.Precipitation != "" {
+		names = append(names, precipitationNames[w.Precipitation])
+	}
+	if w.Obscuration != "" {
+		names = append(names, obscurationNames[w.Obscuration])
+	}
+	if w.Phenomenon != "" {
+		names = append(names, phenomenonNames[w.Phenomenon])
+	}
+	return strings.Join(names, " and ")
+}
+
+func describeSkyCondition(sc SkyCondition) string {
+	clouds := "clouds"
+	switch sc.CloudType {
+	case CumuloNimbus:
+		clouds = "cumulonimbus clouds"
+	case ToweringCumulus:
+		clouds = "towering cumulus clouds"
+	}
+
+	alt := formatFeet(sc.Altitude) + " ft"
+
+	switch sc.Type {
+	case Few:
+		return "few " + clouds + " at " + alt
+	case Scattered:
+		return "scattered " + clouds + " at " + alt
+	case Broken:
+		return "broken " + clouds + " at " + alt
+	case Overcast:
+		return "overcast " + clouds + " at " + alt
+	case VerticalVisibility:
+		return "sky obscured, vertical visibility " + alt
+	case SkyClear:
+		return "sky clear"
+	case Clear:
+		return "no clouds below 12,000 ft"
+	default:
+		return clouds + " at " + alt
+	}
+}
+
+func describeIcing(ic Icing) string {
+	out := strings.ToLower(string(ic.Intensity)) + " icing"
+	switch {
+	case ic.InCloud:
+		out += " in cloud"
+	case ic.InPrecipitation:
+		out += " in precipitation"
+	}
+	return out
+}
+
+func describeTurbulence(tb Turbulence) string {
+	out := strings.ToLower(string(tb.Intensity))
+	if tb.Intensity == IntensityModerate || tb.Intensity == IntensitySevere {
+		if tb.Frequent {
+			out += " frequent"
+		} else {
+			out += " occasional"
+		}
+	}
+
+	out += " turbulence"
+	if tb.Intensity == IntensityModerate || tb.Intensity == IntensitySevere {
+		if tb.InCloud {
+			out += " in cloud"
+		} else {
+			out += " in clear air"
+		}
+	}
+	return out
+}
+
+// describeLayer describes the altitudes of an icing or turbulence layer
+func describeLayer(desc string, base, thickness units.Height) string {
+	if thickness.Value == 0 {
+		return fmt.Sprintf("%s from %s ft", desc, formatFeet(base))
+	}
+	top := units.HeightFeet(base.In(units.HeightInFeet) + thickness.In(units.HeightInFeet))
+	return fmt.Sprintf("%s from %s ft to %s ft", desc, formatFeet(base), formatFeet(top))
+}
+
+func describeAltimeter(alt Altimeter) string {
+	switch alt.Unit {
+	case units.InchesOfMercury:
+		return fmt.Sprintf("%.2f inHg", alt.Value)
+	case units.MillimetersOfMercury:
+		return fmt.Sprintf("%.0f mmHg", alt.Value)
+	default:
+		return fmt.Sprintf("%.0f hPa", alt.Value)
+	}
+}
+
+func describeSpeedUnit(s units.Speed) string {
+	switch s {
+	case units.Knots:
+		return "knots"
+	case units.MetersPerSecond:
+		return "m/s"
+	case units.KilometersPerHour:
+		return "km/h"
+	case units.MilesPerHour:
+		return "mph"
+	default:
+		return string(s)
+	}
+}
+
+func describeDistanceUnit(d units.Distance, singular bool) string {
+	var out string
+	switch d {
+	case units.Miles:
+		out = "statute miles"
+	case units.Meters:
+		out = "meters"
+	case units.Kilometers:
+		out = "kilometers"
+	case units.Feet:
+		out = "feet"
+	default:
+		return string(d)
+	}
+
+	if singular {
+		if d == units.Feet {
+			return "foot"
+		}
+		return strings.TrimSuffix(out, "s")
+	}
+	return out
+}
+
+var descriptorNames = map[Descriptor]string{
+	Shallow:     "shallow",
+	LowDrifting: "low drifting",
+	Blowing:     "blowing",
+	Freezing:    "freezing",
+	Partial:     "partial",
+}
+
+var precipitationNames = map[Precipitation]string{
+	Drizzle:     "drizzle",
+	Rain:        "rain",
+	Snow:        "snow",
+	SnowGrains:  "snow grains",
+	IceCrystals: "ice crystals",
+	IcePellets:  "ice pellets",
+	Hail:        "hail",
+	SmallHail:   "small hail",
+	Unknown:     "unknown precipitation",
+}
+
+var obscurationNames = map[Obscuration]string{
+	Mist:        "mist",
+	Fog:         "fog",
+	Smoke:       "smoke",
+	Dust:        "dust",
+	Sand:        "sand",
+	Haze:        "haze",
+	Spray:       "spray",
+	VolcanicAsh: "volcanic ash",
+}
+
+var phenomenonNames = map[Phenomenon]string{
+	Whirls:      "dust whirls",
+	Squalls:     "squalls",
+	FunnelCloud: "funnel clouds",
+	Sandstorm:   "sandstorm",
+	Duststorm:   "duststorm",
+}
+
+// sentence capitalizes the first letter of s and ends it with a period
+func sentence(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:] + "."
+}
+
+// formatThousands formats an integer with commas separating thousands
+func formatThousands(n int) string {
+	s := strconv.Itoa(n)
+	if n < 0 {
+		return "-" + formatThousands(-n)
+	}
+
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return s
+}
+
+// formatFeet formats a height in whole feet with commas separating thousands
+func formatFeet(h units.Height) string {
+	return formatThousands(int(math.Round(h.In(units.HeightInFeet))))
+}
@@ -0,0 +1,37 @@
+package taf
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDescribe(t *testing.T) {
+	const data = `TAF KLAX 211130Z 2112/2218 26012G20KT P6SM -SHRA FEW035 BKN250CB TX25/2200Z
+  TEMPO 2114/2118 3SM BR
+  PROB30 2118/2222 VCTS
+  FM220600 VRB03KT 1/2SM FZFG VV002`
+
+	fc, err := DecodeWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	expected := `Forecast for KLAX (Los Angeles International Airport), issued Aug 21 11:30Z, valid from Aug 21 12:00Z to Aug 22 18:00Z.
+Wind 260° at 12 knots gusting to 20 knots, visibility more than 6 statute miles, light rain showers, few clouds at 3,500 ft, broken cumulonimbus clouds at 25,000 ft, maximum temperature 25°C at Aug 22 00:00Z.
+Temporarily from Aug 21 14:00Z to Aug 21 18:00Z: visibility 3 statute miles, mist.
+30% chance from Aug 21 18:00Z to Aug 22 22:00Z: thunderstorms in the vicinity.
+From Aug 22 06:00Z: variable wind at 3 knots, visibility 1/2 statute mile, freezing fog, sky obscured, vertical visibility 200 ft.`
+
+	if desc := fc.Describe(DescribeOptions{}); desc != expected {
+		t.Errorf("Unexpected description:\n%s", desc)
+	}
+
+	local := fc.Describe(DescribeOptions{LocalTime: true})
+	if !strings.Contains(local, "issued Aug 21 04:30 PDT") {
+		t.Errorf("Expected local times in description, got:\n%s", local)
+	}
+}
@@ -0,0 +1,263 @@
+package taf
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"go.elara.ws/taf/units"
+)
+
+// DiffField represents a part of the forecast conditions compared by Diff.
+type DiffField string
+
+// Diff Fields
+const (
+	DiffWind       DiffField = "wind"
+	DiffVisibility DiffField = "visibility"
+	DiffCeiling    DiffField = "ceiling"
+	DiffWeather    DiffField = "weather"
+	DiffCategory   DiffField = "category"
+
+	// The worst-case fields take the alternate conditions, such as TEMPO
+	// and PROB groups, into account as well as the prevailing conditions.
+	DiffWorstVisibility DiffField = "worst_visibility"
+	DiffWorstCeiling    DiffField = "worst_ceiling"
+	DiffWorstCategory   DiffField = "worst_category"
+)
+
+// diffFields contains the fields compared by Diff, in the order
+// in which differences are reported for the same period.
+var diffFields = []DiffField{
+	DiffWind, DiffVisibility, DiffCeiling, DiffWeather, DiffCategory,
+	DiffWorstVisibility, DiffWorstCeiling, DiffWorstCategory,
+}
+
+// worstFields maps each worst-case field to the
+// field for the prevailing conditions.
+var worstFields = map[DiffField]DiffField{
+	DiffWorstVisibility: DiffVisibility,
+	DiffWorstCeiling:    DiffCeiling,
+	DiffWorstCategory:   DiffCategory,
+}
+
+// atWorst is appended to the descriptions of the worst-case fields
+const atWorst = " at worst"
+
+// Difference describes a difference between the conditions
+// forecast by two reports during a period.
+type Difference struct {
+	// Field is the part of the conditions that's different.
+	Field DiffField `json:"field"`
+
+	// Valid is the period during which the conditions are different.
+	Valid ValidPair `json:"valid"`
+
+	// Old describes the conditions in the old forecast.
+	Old string `json:"old"`
+
+	// New describes the conditions in the new forecast.
+	New string `json:"new"`
+}
+
+// String returns a plain-language description of the difference
+func (d Difference) String() string {
+	return sentence(describer{loc: time.UTC}.period(d.Valid) + ", " + d.New + " instead of " + d.Old)
+}
+
+// Diff compares the conditions forecast by old and new during the period
+// in which both of them are valid, and returns the differences in
+// chronological order. The wind, visibility, ceiling, weather, and FAA
+// flight category of the prevailing conditions are compared, along with
+// the worst visibility, ceiling, and category once alternate conditions
+// such as TEMPO and PROB groups are taken into account. Speeds and
+// distances in new are converted to the units used by old before
+// they're compared.
+func Diff(old, new *Forecast) []Difference {
+	start := old.Valid.From
+	if new.Valid.From.After(start) {
+		start = new.Valid.From
+	}
+
+	end := old.Valid.To
+	if new.Valid.To.Before(end) {
+		end = new.Valid.To
+	}
+
+	if !start.Before(end) {
+		return nil
+	}
+
+	// The conditions can only change at the start or end of a
+	// group, so those are the only times that need to be compared.
+	times := groupBoundaries(start, end, old, new)
+
+	var out []Difference
+	// last contains the index of the last difference for each field
+	last := map[DiffField]int{}
+	for i, t := range times {
+		to := end
+		if i+1 < len(times) {
+			to = times[i+1]
+		}
+
+		oldCond := old.At(t)
+		oldDesc := describeDiffFields(oldCond)
+		newDesc := describeDiffFields(convertConditions(new.At(t), oldCond))
+
+		for _, field := range diffFields {
+			if oldDesc[field] == newDesc[field] {
+				continue
+			}
+
+			// Worst-case differences that only repeat a
+			// difference in the prevailing conditions are skipped.
+			if base, ok := worstFields[field]; ok &&
+				oldDesc[field] == oldDesc[base]+atWorst &&
+				newDesc[field] == newDesc[base]+atWorst {
+				continue
+			}
+
+			// Extend the previous difference if it's the same and
+			// it ends right where this one starts.
+			if j, ok := last[field]; ok {
+				prev := &out[j]
+				if prev.Valid.To.Equal(t) && prev.Old == oldDesc[field] && prev.New == newDesc[field] {
+					prev.Valid.To = to
+					prev.Valid.Duration = to.Sub(prev.Valid.From)
+					continue
+				}
+			}
+
+			last[field] = len(out)
+			out = append(out, Difference{
+				Field: field,
+				Valid: ValidPair{From: t, To: to, Duration: to.Sub(t)},
+				Old:   oldDesc[field],
+				New:   newDesc[field],
+			})
+		}
+	}
+
+	return out
+}
+
+// convertConditions converts the speeds and distances in c
+// to the units used in ref.
+func convertConditions(c, ref Conditions) Conditions {
+	if unit := ref.Wind.Speed.Unit; c.Wind.Speed.Unit != "" && unit != "" && c.Wind.Speed.Unit != unit {
+		c.Wind.Speed = units.Velocity{Value: units.Nearest.Round(c.Wind.Speed.In(unit)), Unit: unit}
+		c.Wind.Gusts = units.Velocity{Value: units.Nearest.Round(c.Wind.Gusts.In(unit)), Unit: unit}
+	}
+
+	if unit := ref.Visibility.Unit; unit != "" {
+		c.Visibility = convertVisibility(c.Visibility, unit)
+		for i := range c.Alternates {
+			c.Alternates[i].Visibility = convertVisibility(c.Alternates[i].Visibility, unit)
+		}
+	}
+
+	return c
+}
+
+// convertVisibility converts a visibility to the given unit
+func convertVisibility(v Visibility, unit units.Distance) Visibility {
+	if v.Unit != "" && v.Unit != unit {
+		v.Length = units.Length{Value: v.In(unit), Unit: unit}
+	}
+	return v
+}
+
+// describeDiffFields describes each of the fields compared by Diff
+func describeDiffFields(c Conditions) map[DiffField]string {
+	out := map[DiffField]string{
+		DiffWind:     describeDiffWind(c.Wind),
+		DiffCategory: string(c.FlightCategory()) + " conditions",
+	}
+
+	out[DiffVisibility] = describeDiffVisibility(diffVisibility(c.Visibility, c.Flags))
+	out[DiffCeiling] = describeDiffCeiling(c.Ceiling())
+
+	if len(c.Weather) == 0 {
+		out[DiffWeather] = "no significant weather"
+	} else {
+		weather := make([]string, len(c.Weather))
+		for i, w := range c.Weather {
+			weather[i] = describeWeather(w)
+		}
+		out[DiffWeather] = strings.Join(weather, ", ")
+	}
+
+	// Start with the prevailing conditions, then replace
+	// them with any alternate that's worse.
+	vis := diffVisibility(c.Visibility, c.Flags)
+	cig, hasCig := c.Ceiling()
+	cat := c.FlightCategory()
+	for _, alt := range c.Alternates {
+		if v := diffVisibility(alt.Visibility, alt.Flags); v.Unit != "" && (vis.Unit == "" || visibilityBelow(v, vis, false)) {
+			vis = v
+		}
+
+		if h, ok := alt.Ceiling(); ok && (!hasCig || h.Less(cig)) {
+			cig, hasCig = h, true
+		}
+
+		if ac := alt.FlightCategory(); categoryRank(ac) < categoryRank(cat) {
+			cat = ac
+		}
+	}
+
+	out[DiffWorstVisibility] = describeDiffVisibility(vis) + atWorst
+	out[DiffWorstCeiling] = describeDiffCeiling(cig, hasCig) + atWorst
+	out[DiffWorstCategory] = string(cat) + " conditions" + atWorst
+
+	return out
+}
+
+// diffVisibility returns the visibility implied by a
+// visibility group and CAVOK.
+func diffVisibility(v Visibility, flags []Flag) Visibility {
+	if v.Unit == "" && slices.Contains(flags, CeilingAndVisibilityOK) {
+		return cavokVisibility
+	}
+	return v
+}
+
+// describeDiffVisibility describes a visibility for Diff
+func describeDiffVisibility(v Visibility) string {
+	if v.Unit == "" {
+		return "no visibility forecast"
+	}
+	return describeVisibility(v)
+}
+
+// describeDiffWind describes a wind for Diff
+func describeDiffWind(w Wind) string {
+	if w.Speed.Unit == "" {
+		return "no wind forecast"
+	}
+	return describeWind(w)
+}
+
+// describeDiffCeiling describes a ceiling for Diff
+func describeDiffCeiling(alt units.Height, ok bool) string {
+	if !ok {
+		return "no ceiling"
+	}
+	return "ceiling at " + formatFeet(alt) + " ft"
+}
+
+// categoryRank returns the position of an FAA flight category from the
+// worst to the best. Unknown categories are ranked after all the others.
+func categoryRank(cat FlightCategory) int {
+	for i, rule := range FAACategories.Rules {
+		if rule.Category == cat {
+			return i
+		}
+	}
+
+	if cat == FAACategories.Default {
+		return len(FAACategories.Rules)
+	}
+	return len(FAACategories.Rules) + 1
+}
@@ -0,0 +1,143 @@
+package taf
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func TestDiff(t *testing.T) {
+	const oldData = `TAF EGLL 211100Z 2112/2218 22008KT 9999 FEW040
+  BECMG 2118/2120 BKN012
+  FM220600 24010KT CAVOK`
+
+	const newData = `TAF AMD EGLL 211500Z 2115/2218 22008KT 9999 FEW040
+  BECMG 2118/2120 BKN007
+  FM220600 24012MPS CAVOK`
+
+	opts := Options{Month: time.August, Year: 2023}
+
+	oldFc, err := DecodeWithOptions(strings.NewReader(oldData), opts)
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	newFc, err := DecodeWithOptions(strings.NewReader(newData), opts)
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	period := func(fromDay, fromHour, toDay, toHour int) ValidPair {
+		from := time.Date(2023, time.August, fromDay, fromHour, 0, 0, 0, time.UTC)
+		to := time.Date(2023, time.August, toDay, toHour, 0, 0, 0, time.UTC)
+		return ValidPair{From: from, To: to, Duration: to.Sub(from)}
+	}
+
+	expected := []Difference{
+		// The ceiling may already be lower while the BECMG group is in progress
+		{
+			Field: DiffWorstCeiling,
+			Valid: period(21, 18, 21, 20),
+			Old:   "ceiling at 1,200 ft at worst",
+			New:   "ceiling at 700 ft at worst",
+		},
+		{
+			Field: DiffWorstCategory,
+			Valid: period(21, 18, 21, 20),
+			Old:   "MVFR conditions at worst",
+			New:   "IFR conditions at worst",
+		},
+		{
+			Field: DiffCeiling,
+			Valid: period(21, 20, 22, 6),
+			Old:   "ceiling at 1,200 ft",
+			New:   "ceiling at 700 ft",
+		},
+		{
+			Field: DiffCategory,
+			Valid: period(21, 20, 22, 6),
+			Old:   "MVFR conditions",
+			New:   "IFR conditions",
+		},
+		{
+			Field: DiffWind,
+			Valid: period(22, 6, 22, 18),
+			Old:   "wind 240° at 10 knots",
+			New:   "wind 240° at 23 knots",
+		},
+	}
+
+	diffs := Diff(oldFc, newFc)
+	if diff := deep.Equal(diffs, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	const summary = "From Aug 21 20:00Z to Aug 22 06:00Z, ceiling at 700 ft instead of ceiling at 1,200 ft."
+	if s := diffs[2].String(); s != summary {
+		t.Errorf("Expected %q, got %q", summary, s)
+	}
+}
+
+func TestDiffAlternates(t *testing.T) {
+	const oldData = `TAF EGLL 211100Z 2112/2218 22008KT 9999 FEW040`
+
+	const newData = `TAF AMD EGLL 211500Z 2115/2218 22008KT 9999 FEW040
+  TEMPO 2202/2206 TSRA BKN008`
+
+	opts := Options{Month: time.August, Year: 2023}
+
+	oldFc, err := DecodeWithOptions(strings.NewReader(oldData), opts)
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	newFc, err := DecodeWithOptions(strings.NewReader(newData), opts)
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	from := time.Date(2023, time.August, 22, 2, 0, 0, 0, time.UTC)
+	to := time.Date(2023, time.August, 22, 6, 0, 0, 0, time.UTC)
+	valid := ValidPair{From: from, To: to, Duration: to.Sub(from)}
+
+	expected := []Difference{
+		{
+			Field: DiffWorstCeiling,
+			Valid: valid,
+			Old:   "no ceiling at worst",
+			New:   "ceiling at 800 ft at worst",
+		},
+		{
+			Field: DiffWorstCategory,
+			Valid: valid,
+			Old:   "VFR conditions at worst",
+			New:   "IFR conditions at worst",
+		},
+	}
+
+	if diff := deep.Equal(Diff(oldFc, newFc), expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestDiffWind(t *testing.T) {
+	opts := Options{Month: time.August, Year: 2023}
+
+	// A report without a wind group doesn't forecast calm wind
+	oldFc, err := DecodeWithOptions(strings.NewReader("TAF EGLL 211100Z 2112/2218 00000KT 9999 FEW040"), opts)
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	newFc, err := DecodeWithOptions(strings.NewReader("TAF AMD EGLL 211500Z 2115/2218 9999 FEW040"), opts)
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	diffs := Diff(oldFc, newFc)
+	if len(diffs) != 1 || diffs[0].Field != DiffWind || diffs[0].Old != "calm wind" || diffs[0].New != "no wind forecast" {
+		t.Errorf("Unexpected differences: %v", diffs)
+	}
+}
@@ -0,0 +1,98 @@
+package taf
+
+import "go.elara.ws/taf/i18n"
+
+// DisplayName returns the name of the report type in the given locale
+func (rt ReportType) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "ReportType", string(rt))
+}
+
+// DisplayName returns the name of the sky condition type in the given locale
+func (st SkyConditionType) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "SkyConditionType", string(st))
+}
+
+// DisplayName returns the name of the cloud type in the given locale
+func (ct CloudType) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "CloudType", string(ct))
+}
+
+// DisplayName returns the name of the modifier in the given locale
+func (m Modifier) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "Modifier", string(m))
+}
+
+// DisplayName returns the name of the descriptor in the given locale
+func (d Descriptor) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "Descriptor", string(d))
+}
+
+// DisplayName returns the name of the precipitation in the given locale
+func (p Precipitation) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "Precipitation", string(p))
+}
+
+// DisplayName returns the name of the obscuration in the given locale
+func (o Obscuration) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "Obscuration", string(o))
+}
+
+// DisplayName returns the name of the phenomenon in the given locale
+func (p Phenomenon) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "Phenomenon", string(p))
+}
+
+// DisplayName returns the name of the temperature type in the given locale
+func (tt TemperatureType) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "TemperatureType", string(tt))
+}
+
+// DisplayName returns the name of the change type in the given locale
+func (ct ChangeType) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "ChangeType", string(ct))
+}
+
+// DisplayName returns the name of the flag in the given locale
+func (f Flag) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "Flag", string(f))
+}
+
+// DisplayName returns the name of the intensity in the given locale
+func (i Intensity) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "Intensity", string(i))
+}
+
+// DisplayName returns the name of the tendency in the given locale
+func (t RVRTendency) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "RVRTendency", string(t))
+}
+
+// DisplayName returns the name of the runway deposit in the given locale
+func (rd RunwayDeposit) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "RunwayDeposit", string(rd))
+}
+
+// DisplayName returns the name of the braking action in the given locale
+func (ba BrakingAction) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "BrakingAction", string(ba))
+}
+
+// DisplayName returns the name of the flight category in the given locale
+func (fc FlightCategory) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "FlightCategory", string(fc))
+}
+
+// DisplayName returns the name of the kind of group in the given locale
+func (gk GroupKind) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "GroupKind", string(gk))
+}
+
+// DisplayName returns the name of the severity in the given locale
+func (s Severity) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "Severity", string(s))
+}
+
+// DisplayName returns the name of the compared field in the given locale
+func (df DiffField) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "DiffField", string(df))
+}
@@ -0,0 +1,43 @@
+package taf
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go.elara.ws/taf/i18n"
+	"go.elara.ws/taf/units"
+)
+
+func TestDisplayName(t *testing.T) {
+	if name := Thunderstorm.DisplayName("de"); name != "Gewitter" {
+		t.Errorf("Expected Gewitter, got %q", name)
+	}
+
+	if name := units.Knots.DisplayName("fr"); name != "Nœuds" {
+		t.Errorf("Expected Nœuds, got %q", name)
+	}
+
+	fc, err := DecodeWithOptions(strings.NewReader("EGLL 211658Z 2118/2224 22008KT 9999 -RA BKN040"), Options{
+		Month: time.August,
+		Year:  2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	labels := i18n.Labels(fc, "es")
+	expected := map[string]string{
+		"Modifier.Light":          "Ligero",
+		"Precipitation.Rain":      "Lluvia",
+		"SkyConditionType.Broken": "Fragmentadas",
+		"Speed.Knots":             "Nudos",
+		"Distance.Meters":         "Metros",
+	}
+
+	for id, msg := range expected {
+		if labels[id] != msg {
+			t.Errorf("Expected label %q to be %q, got %q", id, msg, labels[id])
+		}
+	}
+}
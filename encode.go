@@ -0,0 +1,411 @@
+package taf
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.elara.ws/taf/units"
+)
+
+// Encode writes the TAF representation of a Forecast to a writer.
+// Each change and probability group is written on its own line,
+// indented by two spaces.
+func Encode(w io.Writer, fc *Forecast) error {
+	_, err := io.WriteString(w, fc.String())
+	return err
+}
+
+// String returns the TAF representation of the forecast.
+func (fc *Forecast) String() string {
+	sb := &strings.Builder{}
+
+	if fc.Header {
+		sb.WriteString("TAF ")
+	}
+
+	var groups []string
+	if rt := encodeReportType(fc.ReportType); rt != "" {
+		groups = append(groups, rt)
+	}
+
+	if fc.Identifier != "" {
+		groups = append(groups, fc.Identifier)
+	}
+
+	if !fc.PublishTime.IsZero() {
+		groups = append(groups, fc.PublishTime.Format(TimeFormat)+"Z")
+	}
+
+	if slices.Contains(fc.Flags, Missing) {
+		groups = append(groups, "NIL")
+	}
+
+	if !fc.Valid.From.IsZero() {
+		groups = append(groups, encodeValid(fc.Valid))
+	}
+
+	if slices.Contains(fc.Flags, Cancelled) {
+		groups = append(groups, "CNL")
+	}
+
+	groups = append(groups, encodeConditions(conditionGroups{
+		Wind:         fc.Wind,
+		WindShear:    fc.WindShear,
+		Visibility:   fc.Visibility,
+		Weather:      fc.Weather,
+		SkyCondition: fc.SkyCondition,
+		Flags:        fc.Flags,
+		Icing:        fc.Icing,
+		Turbulence:   fc.Turbulence,
+		Altimeter:    fc.Altimeter,
+		Temperature:  fc.Temperature,
+	})...)
+	sb.WriteString(strings.Join(groups, " "))
+
+	fc.forEachGroup(func(ch *Change) {
+		sb.WriteString("\n  ")
+		sb.WriteString(ch.String())
+	}, func(pr *Probability) {
+		sb.WriteString("\n  ")
+		sb.WriteString(pr.String())
+	})
+
+	if fc.Remark != "" {
+		sb.WriteString("\n  RMK ")
+		sb.WriteString(fc.Remark)
+	}
+
+	return sb.String()
+}
+
+// forEachGroup calls change for each change and prob for each
+// probability in the forecast. Changes and probabilities are stored
+// separately, so this merges them back together in the order they
+// were decoded in, or in chronological order for groups that
+// weren't decoded from text.
+func (fc *Forecast) forEachGroup(change func(*Change), prob func(*Probability)) {
+	ci, pi := 0, 0
+	for ci < len(fc.Changes) || pi < len(fc.Probabilities) {
+		if pi >= len(fc.Probabilities) ||
+			(ci < len(fc.Changes) && changeFirst(fc.Changes[ci], fc.Probabilities[pi])) {
+			change(fc.Changes[ci])
+			ci++
+		} else {
+			prob(fc.Probabilities[pi])
+			pi++
+		}
+	}
+}
+
+// changeFirst checks whether ch should be encoded before pr
+func changeFirst(ch *Change, pr *Probability) bool {
+	if ch.order != 0 && pr.order != 0 {
+		return ch.order < pr.order
+	}
+	return !pr.Valid.From.Before(ch.Valid.From)
+}
+
+// String returns the TAF representation of the change. If the change
+// has a probability, the PROB group is written before it on the same
+// line, unless it was decoded from text with the PROB group on its own line.
+func (ch *Change) String() string {
+	sb := &strings.Builder{}
+
+	if ch.Probability != 0 {
+		sb.WriteString("PROB")
+		sb.WriteString(strconv.Itoa(ch.Probability))
+		if ch.probOnOwnLine {
+			sb.WriteString("\n  ")
+		} else {
+			sb.WriteByte(' ')
+		}
+	}
+
+	var groups []string
+	switch ch.Type {
+	case From:
+		groups = append(groups, "FM"+ch.Valid.From.Format(TimeFormat))
+	case Becoming:
+		groups = append(groups, "BECMG", encodeValid(ch.Valid))
+	case Temporary:
+		groups = append(groups, "TEMPO", encodeValid(ch.Valid))
+	}
+
+	groups = append(groups, encodeConditions(conditionGroups{
+		Wind:         ch.Wind,
+		WindShear:    ch.WindShear,
+		Visibility:   ch.Visibility,
+		Weather:      ch.Weather,
+		SkyCondition: ch.SkyCondition,
+		Flags:        ch.Flags,
+		Icing:        ch.Icing,
+		Turbulence:   ch.Turbulence,
+		Altimeter:    ch.Altimeter,
+		Temperature:  ch.Temperature,
+	})...)
+	sb.WriteString(strings.Join(groups, " "))
+	return sb.String()
+}
+
+// String returns the TAF representation of the probability group.
+func (pr *Probability) String() string {
+	groups := []string{"PROB" + strconv.Itoa(pr.Value), encodeValid(pr.Valid)}
+	groups = append(groups, encodeConditions(conditionGroups{
+		Wind:         pr.Wind,
+		WindShear:    pr.WindShear,
+		Visibility:   pr.Visibility,
+		Weather:      pr.Weather,
+		SkyCondition: pr.SkyCondition,
+		Flags:        pr.Flags,
+		Icing:        pr.Icing,
+		Turbulence:   pr.Turbulence,
+		Altimeter:    pr.Altimeter,
+		Temperature:  pr.Temperature,
+	})...)
+	return strings.Join(groups, " ")
+}
+
+// conditionGroups holds the groups shared by forecasts,
+// changes, and probabilities.
+type conditionGroups struct {
+	Wind         Wind
+	WindShear    Wind
+	Visibility   Visibility
+	Weather      []Weather
+	SkyCondition []SkyCondition
+	Flags        []Flag
+	Icing        []Icing
+	Turbulence   []Turbulence
+	Altimeter    Altimeter
+	Temperature  []Temperature
+}
+
+// encodeConditions encodes the groups shared by forecasts,
+// changes, and probabilities, in the order they appear in a TAF report.
+func encodeConditions(c conditionGroups) []string {
+	var out []string
+
+	if c.Wind.Speed.Unit != "" {
+		out = append(out, encodeWind(c.Wind))
+	}
+
+	if slices.Contains(c.Flags, CeilingAndVisibilityOK) {
+		out = append(out, "CAVOK")
+	}
+
+	if c.Visibility.Unit != "" {
+		out = append(out, encodeVisibility(c.Visibility))
+	}
+
+	for _, w := range c.Weather {
+		out = append(out, encodeWeather(w))
+	}
+	if slices.Contains(c.Flags, NoSignificantWeather) {
+		out = append(out, "NSW")
+	}
+
+	for _, sc := range c.SkyCondition {
+		out = append(out, encodeSkyCondition(sc))
+	}
+	if slices.Contains(c.Flags, NoSignificantCloud) {
+		out = append(out, "NSC")
+	}
+	if slices.Contains(c.Flags, NoCloudDetected) {
+		out = append(out, "NCD")
+	}
+
+	if c.WindShear.Speed.Unit != "" {
+		out = append(out, encodeWind(c.WindShear))
+	}
+
+	for _, ic := range c.Icing {
+		out = append(out, encodeIcing(ic))
+	}
+
+	for _, tb := range c.Turbulence {
+		out = append(out, encodeTurbulence(tb))
+	}
+
+	if c.Altimeter.Unit != "" {
+		out = append(out, encodeQNH(c.Altimeter))
+	}
+
+	for _, temp := range c.Temperature {
+		out = append(out, encodeTemperature(temp))
+	}
+
+	return out
+}
+
+// encodeValid encodes a validity period as DDHH/DDHH.
+func encodeValid(vp ValidPair) string {
+	return vp.From.Format(ValidFormat) + "/" + encodeEndTime(vp.To)
+}
+
+// encodeEndTime encodes the end of a period. Periods that
+// end at midnight are written as hour 24 of the previous day,
+// which is the inverse of what parseValidTime does.
+func encodeEndTime(t time.Time) string {
+	if t.Hour() == 0 {
+		return fmt.Sprintf("%02d24", t.AddDate(0, 0, -1).Day())
+	}
+	return t.Format(ValidFormat)
+}
+
+func encodeWind(w Wind) string {
+	sb := &strings.Builder{}
+
+	if w.WindShear.Value != 0 {
+		fmt.Fprintf(sb, "WS%03d/", hundredsOfFeet(w.WindShear))
+	}
+
+	unit := w.Speed.Unit
+	// TAF reports have no code for miles per hour, so use knots instead
+	if unit == units.MilesPerHour {
+		unit = units.Knots
+	}
+	speed := int(units.Nearest.Round(w.Speed.In(unit)))
+	gusts := int(units.Nearest.Round(w.Gusts.In(unit)))
+
+	if w.Direction.Variable {
+		sb.WriteString("VRB")
+	} else {
+		fmt.Fprintf(sb, "%03d", w.Direction.Value)
+	}
+
+	fmt.Fprintf(sb, "%02d", speed)
+	if gusts != 0 {
+		fmt.Fprintf(sb, "G%02d", gusts)
+	}
+
+	switch unit {
+	case units.MetersPerSecond:
+		sb.WriteString("MPS")
+	case units.KilometersPerHour:
+		sb.WriteString("KMH")
+	default:
+		sb.WriteString("KT")
+	}
+
+	return sb.String()
+}
+
+func encodeVisibility(v Visibility) string {
+	sb := &strings.Builder{}
+
+	if v.Plus {
+		sb.WriteString("P")
+	} else if v.Minus {
+		sb.WriteString("M")
+	}
+
+	if v.Unit == units.Miles {
+		sb.WriteString(formatMixedNumber(v.Value))
+		sb.WriteString("SM")
+		return sb.String()
+	}
+
+	// Anything other than statute miles is written in meters
+	val := int(math.Round(v.In(units.Meters)))
+	if val > 9999 {
+		val = 9999
+	}
+	fmt.Fprintf(sb, "%04d", val)
+	return sb.String()
+}
+
+// formatMixedNumber formats a number as a mixed number such as
+// "1 1/2", using sixteenths as the smallest fraction.
+func formatMixedNumber(val float64) string {
+	whole := int(val)
+	num := int(math.Round((val - float64(whole)) * 16))
+	if num == 16 {
+		whole++
+		num = 0
+	}
+
+	if num == 0 {
+		return strconv.Itoa(whole)
+	}
+
+	den := 16
+	for num%2 == 0 {
+		num /= 2
+		den /= 2
+	}
+
+	if whole == 0 {
+		return fmt.Sprintf("%d/%d", num, den)
+	}
+	return fmt.Sprintf("%d %d/%d", whole, num, den)
+}
+
+func encodeWeather(w Weather) string {
+	sb := &strings.Builder{}
+
+	if w.Vicinity {
+		sb.WriteString("VC")
+	} else {
+		switch w.Modifier {
+		case Heavy:
+			sb.WriteString("+")
+		case Light:
+			sb.WriteString("-")
+		}
+	}
+
+	sb.WriteString(encodeDescriptor(w.Descriptor))
+	sb.WriteString(encodePrecipitation(w.Precipitation))
+	sb.WriteString(encodeObscuration(w.Obscuration))
+	sb.WriteString(encodePhenomenon(w.Phenomenon))
+	return sb.String()
+}
+
+func encodeSkyCondition(sc SkyCondition) string {
+	sb := &strings.Builder{}
+	sb.WriteString(encodeSkyConditionType(sc.Type))
+	if sc.Type != SkyClear && sc.Type != Clear {
+		// Scale factor for altitude is 100
+		fmt.Fprintf(sb, "%03d", hundredsOfFeet(sc.Altitude))
+	}
+	sb.WriteString(encodeCloudType(sc.CloudType))
+	return sb.String()
+}
+
+func encodeTemperature(t Temperature) string {
+	prefix := "TX"
+	if t.Type == Low {
+		prefix = "TN"
+	}
+
+	// TAF reports always give temperatures in degrees Celsius
+	celsius := t.Value
+	if t.Unit != "" {
+		celsius = t.Unit.ConvertInt(units.Celsius, t.Value, units.Nearest)
+	}
+
+	// Negative temperatures are prefixed with M
+	val := fmt.Sprintf("%02d", celsius)
+	if celsius < 0 {
+		val = fmt.Sprintf("M%02d", -celsius)
+	}
+
+	return prefix + val + "/" + t.Time.Format(ValidFormat) + "Z"
+}
+
+// hundredsOfFeet returns a height in hundreds of feet,
+// which is how heights are written in TAF reports
+func hundredsOfFeet(h units.Height) int {
+	return int(math.Round(h.In(units.HeightInFeet) / 100))
+}
+
+// thousandsOfFeet returns a height in thousands of feet, as used
+// in the icing and turbulence groups of military TAFs
+func thousandsOfFeet(h units.Height) int {
+	return int(math.Round(h.In(units.HeightInFeet) / 1000))
+}
@@ -0,0 +1,231 @@
+package taf
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// maxProblems is the maximum number of unrecognized groups
+// the decoder will skip before giving up on a report.
+const maxProblems = 50
+
+// GroupKind represents the kind of group a problem was found in.
+type GroupKind string
+
+// Group Kinds
+const (
+	KindUnknown     GroupKind = "unknown"
+	KindTime        GroupKind = "time"
+	KindWind        GroupKind = "wind"
+	KindVisibility  GroupKind = "visibility"
+	KindSky         GroupKind = "sky"
+	KindTemperature GroupKind = "temp"
+	KindChange      GroupKind = "change"
+	KindProbability GroupKind = "prob"
+	KindRVR         GroupKind = "rvr"
+	KindRunwayState GroupKind = "runway state"
+	KindAltimeter   GroupKind = "altimeter"
+	KindTrend       GroupKind = "trend"
+	KindIcing       GroupKind = "icing"
+	KindTurbulence  GroupKind = "turbulence"
+)
+
+// Problem describes a group that couldn't be decoded.
+type Problem struct {
+	// Group contains the original text of the group.
+	Group string `json:"group,omitempty"`
+
+	// Kind specifies the kind of group the problem was found in.
+	Kind GroupKind `json:"kind,omitempty"`
+
+	// Message describes the problem.
+	Message string `json:"message,omitempty"`
+
+	// Offset is the byte offset of the group within the report.
+	Offset int `json:"offset"`
+
+	// Line is the line on which the group starts, starting at 1.
+	Line int `json:"line,omitempty"`
+
+	// Column is the column at which the group starts, starting at 1.
+	Column int `json:"column,omitempty"`
+}
+
+// Error returns a string describing the problem
+func (p *Problem) Error() string {
+	msg := fmt.Sprintf("%d:%d: %s: %s", p.Line, p.Column, p.Kind, p.Message)
+	if p.Group != "" {
+		msg += fmt.Sprintf(" (%q)", p.Group)
+	}
+	return msg
+}
+
+// DecodeError is returned when a report can't be decoded. It
+// contains every problem that was found in the report.
+type DecodeError struct {
+	Problems []Problem
+}
+
+// Error returns a string describing the problems
+func (e *DecodeError) Error() string {
+	if len(e.Problems) == 1 {
+		return e.Problems[0].Error()
+	}
+
+	msgs := make([]string, len(e.Problems))
+	for i := range e.Problems {
+		msgs[i] = e.Problems[i].Error()
+	}
+	return fmt.Sprintf("%d problems: %s", len(e.Problems), strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the problems as a slice of errors,
+// so that they can be inspected using errors.As.
+func (e *DecodeError) Unwrap() []error {
+	out := make([]error, len(e.Problems))
+	for i := range e.Problems {
+		out[i] = &e.Problems[i]
+	}
+	return out
+}
+
+// problemf creates a new problem at the given position
+func problemf(pos lexer.Position, kind GroupKind, format string, args ...any) *Problem {
+	return &Problem{
+		Kind:    kind,
+		Message: fmt.Sprintf(format, args...),
+		Offset:  pos.Offset,
+		Line:    pos.Line,
+		Column:  pos.Column,
+	}
+}
+
+// problems collects the problems found while decoding a report
+type problems struct {
+	src  string
+	list []Problem
+}
+
+// add adds an error to the problem list. If the error isn't a
+// *Problem, it's added as a problem of an unknown kind.
+func (ps *problems) add(err error) {
+	var p *Problem
+	if !errors.As(err, &p) {
+		p = &Problem{Kind: KindUnknown, Message: err.Error()}
+	}
+
+	if p.Group == "" {
+		start, end := groupBounds(ps.src, p.Offset)
+		p.Group = ps.src[start:end]
+	}
+
+	ps.list = append(ps.list, *p)
+}
+
+// result returns the error that should be returned by the decoder,
+// or nil if there were no problems or lenient is set.
+func (ps *problems) result(lenient bool) error {
+	if len(ps.list) == 0 || lenient {
+		return nil
+	}
+	return &DecodeError{Problems: ps.list}
+}
+
+// unparsed returns the text of each group that had a problem
+func (ps *problems) unparsed() []string {
+	var out []string
+	for _, p := range ps.list {
+		out = append(out, p.Group)
+	}
+	return out
+}
+
+// parseWithRecovery parses src using the given parser. When the parser
+// fails, the group at which it failed is recorded as a problem and replaced
+// with spaces, so that the offsets of the other groups stay the same, and
+// the source is parsed again.
+func parseWithRecovery[T any](p *participle.Parser[T], name, src string, ps *problems) (*T, error) {
+	ps.src = src
+	for {
+		ast, err := p.ParseString(name, src)
+		if err == nil {
+			return ast, nil
+		}
+
+		var perr participle.Error
+		if !errors.As(err, &perr) {
+			ps.add(err)
+			return nil, &DecodeError{Problems: ps.list}
+		}
+
+		pos := perr.Position()
+		if len(ps.list) >= maxProblems {
+			ps.add(problemf(pos, KindUnknown, "too many problems, stopped decoding"))
+			return nil, &DecodeError{Problems: ps.list}
+		}
+
+		start, end := groupBounds(src, pos.Offset)
+		if start == end {
+			// There's nothing that can be skipped, so give up. The parser's
+			// message refers to its tokens, so it isn't used here.
+			msg := "expected a group"
+			if strings.TrimSpace(src) == "" {
+				msg = "no groups could be decoded"
+			} else if strings.TrimSpace(src[start:]) == "" {
+				msg = "report ends unexpectedly"
+			}
+			ps.add(problemf(pos, KindUnknown, "%s", msg))
+			return nil, &DecodeError{Problems: ps.list}
+		}
+
+		line, column := positionOf(src, start)
+		ps.add(&Problem{
+			Group:   src[start:end],
+			Kind:    KindUnknown,
+			Message: "unrecognized group",
+			Offset:  start,
+			Line:    line,
+			Column:  column,
+		})
+
+		src = src[:start] + strings.Repeat(" ", end-start) + src[end:]
+	}
+}
+
+// groupBounds returns the start and end offsets of the group at the
+// given offset. If the offset isn't on a group, such as when it's on
+// whitespace or at the end of src, an empty range at the offset is returned.
+func groupBounds(src string, offset int) (start, end int) {
+	if offset > len(src) {
+		offset = len(src)
+	}
+
+	if offset == len(src) || isSpace(src[offset]) {
+		return offset, offset
+	}
+
+	start, end = offset, offset
+	for start > 0 && !isSpace(src[start-1]) {
+		start--
+	}
+	for end < len(src) && !isSpace(src[end]) {
+		end++
+	}
+	return start, end
+}
+
+// positionOf returns the line and column of an offset within src
+func positionOf(src string, offset int) (line, column int) {
+	before := src[:offset]
+	line = strings.Count(before, "\n") + 1
+	column = offset - strings.LastIndexByte(before, '\n')
+	return line, column
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
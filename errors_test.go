@@ -0,0 +1,146 @@
+package taf
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeError(t *testing.T) {
+	const data = `KLAX 311130Z 3112/0118 26012KT P6SM FEW035 ?????
+  FM010600 25010KT P6SM SCT040 XYZ`
+
+	opts := Options{Reference: time.Date(2024, time.February, 1, 3, 0, 0, 0, time.UTC)}
+
+	_, err := DecodeWithOptions(strings.NewReader(data), opts)
+	var derr *DecodeError
+	if !errors.As(err, &derr) {
+		t.Fatalf("Expected *DecodeError, got %v", err)
+	}
+
+	expected := []Problem{
+		{Group: "?????", Kind: KindUnknown, Message: "unrecognized group", Offset: 43, Line: 1, Column: 44},
+		{Group: "XYZ", Kind: KindUnknown, Message: "unrecognized group", Offset: 80, Line: 2, Column: 32},
+	}
+
+	if len(derr.Problems) != len(expected) {
+		t.Fatalf("Expected %d problems, got %d: %v", len(expected), len(derr.Problems), derr)
+	}
+
+	for i, p := range derr.Problems {
+		if p != expected[i] {
+			t.Errorf("Expected problem %#v, got %#v", expected[i], p)
+		}
+	}
+
+	var p *Problem
+	if !errors.As(err, &p) || p.Group != "?????" {
+		t.Errorf("Expected errors.As to find the first problem, got %v", p)
+	}
+
+	opts.Lenient = true
+	fc, err := DecodeWithOptions(strings.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	if len(fc.Warnings) != 2 {
+		t.Errorf("Expected 2 warnings, got %d", len(fc.Warnings))
+	}
+
+	if strings.Join(fc.Unparsed, " ") != "????? XYZ" {
+		t.Errorf("Expected unparsed groups ????? and XYZ, got %q", fc.Unparsed)
+	}
+
+	if len(fc.Changes) != 1 || len(fc.Changes[0].SkyCondition) != 1 {
+		t.Errorf("Expected the rest of the forecast to be decoded, got %#v", fc.Changes)
+	}
+}
+
+func TestDecodeErrorGiveUp(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		message string
+	}{
+		{"leading whitespace", "  KLAX 311130Z 3112/0118", "expected a group"},
+		{"whitespace", "   ", "no groups could be decoded"},
+		{"no groups", "????? ?????", "no groups could be decoded"},
+		{"too many problems", "KLAX 311130Z 3112/0118" + strings.Repeat(" ?????", maxProblems+5), "too many problems, stopped decoding"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := DecodeString(test.data)
+			var derr *DecodeError
+			if !errors.As(err, &derr) {
+				t.Fatalf("Expected *DecodeError, got %T: %v", err, err)
+			}
+
+			last := derr.Problems[len(derr.Problems)-1]
+			if last.Message != test.message {
+				t.Errorf("Expected last problem to be %q, got %q", test.message, last.Message)
+			}
+		})
+	}
+}
+
+func TestDecodeErrorTruncated(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		offset  int
+		group   string
+		message string
+	}{
+		{"end of report", "KLAX 311130Z 3112/0118 TX25/", 28, "", "report ends unexpectedly"},
+		{"trailing whitespace", "KLAX 311130Z 3112/0118 TX25/  \n ", 28, "", "report ends unexpectedly"},
+		{"whitespace", "KLAX 311130Z 3112/0118 TX25/\n  FM010600 25010KT", 28, "", "expected a group"},
+		{"missing valid period", "KLAX 311130Z 3112/0118 26012KT TEMPO", 31, "TEMPO", "missing validity period"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := DecodeString(test.data)
+			var derr *DecodeError
+			if !errors.As(err, &derr) {
+				t.Fatalf("Expected *DecodeError, got %T: %v", err, err)
+			}
+
+			// The group before the one that's missing shouldn't be blamed
+			last := derr.Problems[len(derr.Problems)-1]
+			if last.Message != test.message || last.Offset != test.offset || last.Group != test.group {
+				t.Errorf("Unexpected problem: %v", &last)
+			}
+		})
+	}
+}
+
+func TestLenientInvalidChange(t *testing.T) {
+	const data = `KLAX 311130Z 3112/0118 26012KT P6SM FEW035
+  FM012599 25010KT P6SM SCT040
+  FM010600 25010KT P6SM BKN040`
+
+	opts := Options{
+		Reference: time.Date(2024, time.February, 1, 3, 0, 0, 0, time.UTC),
+		Lenient:   true,
+	}
+
+	fc, err := DecodeWithOptions(strings.NewReader(data), opts)
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	if len(fc.Warnings) != 1 || fc.Warnings[0].Kind != KindChange {
+		t.Errorf("Expected a single change warning, got %v", fc.Warnings)
+	}
+
+	if len(fc.Changes) != 1 || fc.Changes[0].Valid.From.IsZero() {
+		t.Fatalf("Expected the invalid change to be skipped, got %v", fc.Changes)
+	}
+
+	if len(fc.SkyCondition) != 1 || fc.Changes[0].SkyCondition[0].Type != Broken {
+		t.Errorf("Expected the groups of the invalid change to be skipped, got %v", fc.SkyCondition)
+	}
+}
@@ -0,0 +1,174 @@
+// Package i18n provides localized display names for the values
+// decoded from weather reports.
+//
+// Messages are identified by the name of their type and their
+// value, separated by a dot (e.g. "Precipitation.Rain"). Catalogs
+// for English, French, German, and Spanish are included, and more
+// can be added using Register.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale used when a message
+// isn't available in the requested locale.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localesFS embed.FS
+
+// Catalog maps message IDs to localized messages.
+type Catalog map[string]string
+
+// Displayer is implemented by types that have localized display names.
+type Displayer interface {
+	DisplayName(locale string) string
+}
+
+var (
+	mu       sync.RWMutex
+	catalogs = map[string]Catalog{}
+)
+
+func init() {
+	entries, err := localesFS.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, entry := range entries {
+		data, err := localesFS.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+
+		var c Catalog
+		if err = json.Unmarshal(data, &c); err != nil {
+			panic(err)
+		}
+
+		Register(strings.TrimSuffix(entry.Name(), ".json"), c)
+	}
+}
+
+// Register adds the messages in c to the catalog for the given locale.
+// Messages that already exist are replaced.
+func Register(locale string, c Catalog) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	locale = normalize(locale)
+	if catalogs[locale] == nil {
+		catalogs[locale] = Catalog{}
+	}
+	for id, msg := range c {
+		catalogs[locale][id] = msg
+	}
+}
+
+// Locales returns the locales that have a catalog, in sorted order.
+func Locales() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	out := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		out = append(out, locale)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Lookup returns the message with the given ID in the given locale. If the
+// locale has a region (e.g. "fr-CA") and there's no message for it, the
+// base language is tried next (e.g. "fr"), and then DefaultLocale.
+func Lookup(locale, id string) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	locale = normalize(locale)
+	for {
+		if msg, ok := catalogs[locale][id]; ok {
+			return msg, true
+		}
+
+		i := strings.LastIndexByte(locale, '-')
+		if i == -1 {
+			break
+		}
+		locale = locale[:i]
+	}
+
+	msg, ok := catalogs[DefaultLocale][id]
+	return msg, ok
+}
+
+// DisplayName returns the display name of the value of an enum
+// type in the given locale. If there's no message for the value,
+// the value itself is returned.
+func DisplayName(locale, typeName, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	if msg, ok := Lookup(locale, typeName+"."+value); ok {
+		return msg
+	}
+	return value
+}
+
+// Labels returns the display names of every value in v that implements
+// Displayer, keyed by message ID. Structs, pointers, slices, and maps
+// are searched recursively.
+func Labels(v any, locale string) map[string]string {
+	out := map[string]string{}
+	collectLabels(reflect.ValueOf(v), locale, out)
+	return out
+}
+
+func collectLabels(rv reflect.Value, locale string, out map[string]string) {
+	if !rv.IsValid() {
+		return
+	}
+
+	if rv.Kind() == reflect.String && rv.CanInterface() {
+		if d, ok := rv.Interface().(Displayer); ok && rv.String() != "" {
+			out[rv.Type().Name()+"."+rv.String()] = d.DisplayName(locale)
+		}
+		return
+	}
+
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		collectLabels(rv.Elem(), locale, out)
+	case reflect.Struct:
+		for i := 0; i < rv.NumField(); i++ {
+			if rv.Type().Field(i).IsExported() {
+				collectLabels(rv.Field(i), locale, out)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			collectLabels(rv.Index(i), locale, out)
+		}
+	case reflect.Map:
+		iter := rv.MapRange()
+		for iter.Next() {
+			collectLabels(iter.Value(), locale, out)
+		}
+	}
+}
+
+// normalize converts a locale to lowercase, removes any encoding, and
+// replaces underscores with hyphens, so that "fr_CA.UTF-8" matches "fr-ca".
+func normalize(locale string) string {
+	locale, _, _ = strings.Cut(locale, ".")
+	return strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+}
@@ -0,0 +1,173 @@
+package i18n
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	testCases := []struct {
+		locale   string
+		expected string
+	}{
+		{"fr", "Pluie"},
+		{"fr-CA", "Pluie"},
+		{"de_DE.UTF-8", "Regen"},
+		{"ES", "Lluvia"},
+		{"xx", "Rain"},
+	}
+
+	for _, tc := range testCases {
+		if msg := DisplayName(tc.locale, "Precipitation", "Rain"); msg != tc.expected {
+			t.Errorf("Expected %q for locale %q, got %q", tc.expected, tc.locale, msg)
+		}
+	}
+
+	if msg := DisplayName("fr", "Precipitation", "Unknown value"); msg != "Unknown value" {
+		t.Errorf("Expected value to be returned when there's no message, got %q", msg)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register("it", Catalog{"Precipitation.Rain": "Pioggia"})
+
+	if msg := DisplayName("it-IT", "Precipitation", "Rain"); msg != "Pioggia" {
+		t.Errorf("Expected registered message, got %q", msg)
+	}
+
+	if msg := DisplayName("it", "Precipitation", "Snow"); msg != "Snow" {
+		t.Errorf("Expected English message for missing translation, got %q", msg)
+	}
+}
+
+// TestCatalogs checks that every embedded catalog
+// has the same messages as the English one.
+func TestCatalogs(t *testing.T) {
+	entries, err := localesFS.ReadDir("locales")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	load := func(name string) Catalog {
+		data, err := localesFS.ReadFile(path.Join("locales", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var c Catalog
+		if err = json.Unmarshal(data, &c); err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	en := load(DefaultLocale + ".json")
+	for _, entry := range entries {
+		c := load(entry.Name())
+		for id := range en {
+			if c[id] == "" {
+				t.Errorf("%s: missing message %q", entry.Name(), id)
+			}
+		}
+		for id := range c {
+			if _, ok := en[id]; !ok {
+				t.Errorf("%s: unknown message %q", entry.Name(), id)
+			}
+		}
+	}
+}
+
+// TestCatalogsComplete checks that the English catalog, and so every
+// other catalog, has a message for each value of every enum type in
+// the module that has a DisplayName method.
+func TestCatalogsComplete(t *testing.T) {
+	data, err := localesFS.ReadFile(path.Join("locales", DefaultLocale+".json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var en Catalog
+	if err = json.Unmarshal(data, &en); err != nil {
+		t.Fatal(err)
+	}
+
+	values := enumValues(t, "..")
+	for _, typeName := range []string{"Precipitation", "Speed", "Severity", "DiffField", "Treatment"} {
+		if len(values[typeName]) == 0 {
+			t.Errorf("No values found for %s", typeName)
+		}
+	}
+
+	for typeName, vals := range values {
+		for _, val := range vals {
+			if id := typeName + "." + val; en[id] == "" {
+				t.Errorf("Missing message %q", id)
+			}
+		}
+	}
+}
+
+// enumValues returns the values of the string constants of each type
+// in the module at root that has a DisplayName method, keyed by type name.
+func enumValues(t *testing.T, root string) map[string][]string {
+	fset := token.NewFileSet()
+	displayable := map[string]bool{}
+	consts := map[string][]string{}
+
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(p, ".go") || strings.HasSuffix(p, "_test.go") {
+			return err
+		}
+
+		f, err := parser.ParseFile(fset, p, nil, 0)
+		if err != nil {
+			return err
+		}
+
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Name.Name == "DisplayName" && decl.Recv != nil {
+					if id, ok := decl.Recv.List[0].Type.(*ast.Ident); ok {
+						displayable[id.Name] = true
+					}
+				}
+			case *ast.GenDecl:
+				if decl.Tok != token.CONST {
+					continue
+				}
+				for _, spec := range decl.Specs {
+					vs := spec.(*ast.ValueSpec)
+					typ, ok := vs.Type.(*ast.Ident)
+					if !ok {
+						continue
+					}
+					for _, v := range vs.Values {
+						if lit, ok := v.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+							val, _ := strconv.Unquote(lit.Value)
+							consts[typ.Name] = append(consts[typ.Name], val)
+						}
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := map[string][]string{}
+	for typeName := range displayable {
+		out[typeName] = consts[typeName]
+	}
+	return out
+}
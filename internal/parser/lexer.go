@@ -5,9 +5,11 @@ import (
 )
 
 var lex = lexer.MustSimple([]lexer.SimpleRule{
-	{Name: "header", Pattern: `TAF ?`},
+	{Name: "Header", Pattern: `TAF ?`},
 	{Name: "Type", Pattern: "AMD|COR"},
 	{Name: "Remark", Pattern: `RMK[^\n]*`},
+	{Name: "Icing", Pattern: `6\d{5}\b`},
+	{Name: "Turbulence", Pattern: `5\d{5}\b`},
 	{Name: "Number", Pattern: `\d+`},
 	{Name: "Modifier", Pattern: `[+-]|VC`},
 	{Name: "Prob", Pattern: "PROB"},
@@ -0,0 +1,97 @@
+package parser
+
+import (
+	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+var metarLex = lexer.MustSimple([]lexer.SimpleRule{
+	{Name: "Header", Pattern: `(METAR|SPECI) ?`},
+	{Name: "Remark", Pattern: `RMK[^\n]*`},
+	{Name: "RunwayState", Pattern: `R\d{2}[LCR]?/(CLRD|[\d/]{4})[\d/]{2}`},
+	{Name: "RVR", Pattern: `R\d{2}[LCR]?/[PM]?\d{4}(V[PM]?\d{4})?(FT)?/?[UDN]?`},
+	{Name: "TempDew", Pattern: `M?\d{2}/(M?\d{2})?`},
+	{Name: "Altimeter", Pattern: `[AQ]\d{4}`},
+	{Name: "Number", Pattern: `\d+`},
+	{Name: "Modifier", Pattern: `[+-]|VC`},
+	{Name: "Slash", Pattern: `/`},
+	{Name: "Recent", Pattern: `RE`},
+	{Name: "Descriptor", Pattern: `MI|BC|DR|BL|SH|TS|FZ|PR`},
+	{Name: "Precip", Pattern: `DZ|RA|SN|SG|IC|PL|GR|GS|UP`},
+	{Name: "Obscur", Pattern: "BR|FG|FU|DU|SA|HZ|PY|VA"},
+	{Name: "Phenom", Pattern: "PO|SQ|FC|SS|DS"},
+	{Name: "Ident", Pattern: `[A-Z]+`},
+	{Name: "WS", Pattern: `[ \t\n\r]+`},
+})
+
+type METAR struct {
+	Header string       `@Header?`
+	Items  []*METARItem `@@*`
+}
+
+type METARItem struct {
+	Pos           lexer.Position
+	Time          *string            `( @Number "Z"`
+	TrendTime     *TrendTime         `| @@`
+	Trend         *Trend             `| @@`
+	WindSpeed     *WindSpeed         `| @@`
+	WindVariation *WindVariation     `| @@`
+	RVR           *string            `| @RVR`
+	RunwayState   *string            `| @RunwayState`
+	Visibility    *Visibility        `| @@`
+	SkyCondition  *SkyCondition      `| @@`
+	Vicinity      *Vicinity          `| @@`
+	Weather       *Weather           `| @@`
+	Descriptor    *DescriptorWeather `| @@`
+	Recent        *RecentWeather     `| @@`
+	TempDew       *string            `| @TempDew`
+	Altimeter     *string            `| @Altimeter`
+	Flag          *METARFlag         `| @@`
+	Remark        *string            `| @Remark`
+	ID            *string            `| @Ident ) WS?`
+}
+
+type WindVariation struct {
+	Pos  lexer.Position
+	From string `@Number "V"`
+	To   string `@Number`
+}
+
+type DescriptorWeather struct {
+	Pos        lexer.Position
+	Modifier   string `@Modifier?`
+	Descriptor string `@Descriptor`
+}
+
+type RecentWeather struct {
+	Pos           lexer.Position
+	Descriptor    string `Recent @Descriptor?`
+	Precipitation string `( @Precip`
+	Obscuration   string `| @Obscur`
+	Other         string `| @Phenom )?`
+}
+
+type Trend struct {
+	Pos  lexer.Position
+	Type string `@("BECMG"|"TEMPO")`
+}
+
+type TrendTime struct {
+	Pos  lexer.Position
+	Type string `@("FM"|"TL"|"AT")`
+	Time string `@Number`
+}
+
+type METARFlag struct {
+	Pos       lexer.Position
+	Auto      bool `( @"AUTO"`
+	Corrected bool `| @"COR"`
+	CAVOK     bool `| @"CAVOK"`
+	NSW       bool `| @"NSW"`
+	NSC       bool `| @"NSC"`
+	NCD       bool `| @"NCD"`
+	Nil       bool `| @"NIL"`
+	NoSig     bool `| @"NOSIG" )`
+}
+
+var METARParser = participle.MustBuild[METAR](participle.Lexer(metarLex), participle.UseLookahead(2))
@@ -6,14 +6,16 @@ import (
 )
 
 type AST struct {
-	Type  *string `(@Type WS)?`
-	Items []*Item `@@*`
+	Header bool    `@Header?`
+	Type   *string `(@Type WS)?`
+	Items  []*Item `@@*`
 }
 
 type Item struct {
 	Pos          lexer.Position
+	Tokens       []lexer.Token
 	Time         *string       `( @Number "Z" WS`
-	Valid        *ValidPair    `  @@`
+	Valid        *ValidPair    `  WS? @@?`
 	Probability  *Probability  `| @@`
 	Change       *Change       `| @@`
 	WindSpeed    *WindSpeed    `| @@`
@@ -22,6 +24,9 @@ type Item struct {
 	Vicinity     *Vicinity     `| @@`
 	Weather      *Weather      `| @@`
 	Temperature  *Temperature  `| @@`
+	Altimeter    *Altimeter    `| @@`
+	Icing        *string       `| @Icing`
+	Turbulence   *string       `| @Turbulence`
 	Flag         *Flag         `| @@`
 	Remark       *string       `| @Remark`
 	ID           *string       `| @Ident ) WS?`
@@ -51,13 +56,14 @@ type WindSpeed struct {
 type Visibility struct {
 	Pos   lexer.Position
 	Plus  bool   `@"P"?`
+	Minus bool   `@"M"?`
 	Value string `@Number @WS? @(Number? "/" Number)?`
 	Unit  string `@"SM"?`
 }
 
 type SkyCondition struct {
 	Pos       lexer.Position
-	Type      string `@("FEW"|"SCT"|"BKN"|"OVC"|"VV"|"SKC")`
+	Type      string `@("FEW"|"SCT"|"BKN"|"OVC"|"VV"|"SKC"|"CLR")`
 	Altitude  string `@Number?`
 	CloudType string `@("CB"|"TCU")?`
 }
@@ -86,14 +92,25 @@ type Change struct {
 
 type Temperature struct {
 	Pos   lexer.Position
-	Type  string `@("TX"|"TN")`
+	Type  string `@("TX"|"TN"|"T"|"TXM"|"TNM"|"TM")`
 	Value string `@Number "/"`
 	Time  string `@Number "Z"`
 }
 
+type Altimeter struct {
+	Pos    lexer.Position
+	Value  string `"QNH" @Number`
+	Inches bool   `@"INS"?`
+}
+
 type Flag struct {
-	Pos   lexer.Position
-	CAVOK bool `@"CAVOK"`
+	Pos       lexer.Position
+	CAVOK     bool `( @"CAVOK"`
+	NSW       bool `| @"NSW"`
+	NSC       bool `| @"NSC"`
+	NCD       bool `| @"NCD"`
+	Cancelled bool `| @"CNL"`
+	Nil       bool `| @"NIL" )`
 }
 
 var Parser = participle.MustBuild[AST](participle.Lexer(lex))
@@ -0,0 +1,571 @@
+package taf
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.elara.ws/taf/airports"
+	"go.elara.ws/taf/internal/parser"
+	"go.elara.ws/taf/units"
+)
+
+// METAR represents a Meteorological Aerodrome Report (METAR), which
+// describes the observed weather at a specific airport.
+type METAR struct {
+	// Special indicates whether this is a special report (SPECI) issued
+	// outside of the regular schedule.
+	Special bool `json:"special,omitempty"`
+
+	// ReportType represents the type of report this METAR describes.
+	ReportType ReportType `json:"report_type,omitempty"`
+
+	// Identifier holds the ICAO airport identifier for which this report was issued.
+	Identifier string `json:"identifier,omitempty"`
+
+	// Airport provides additional information about the airport for which this report was issued.
+	Airport airports.Airport `json:"airport,omitempty"`
+
+	// ObservationTime indicates the time at which the conditions were observed.
+	ObservationTime time.Time `json:"observation_time,omitempty"`
+
+	// Wind describes the observed wind conditions.
+	Wind Wind `json:"wind,omitempty"`
+
+	// WindVariation describes the range of directions the wind is varying between.
+	WindVariation WindVariation `json:"wind_variation,omitempty"`
+
+	// Visibility describes the observed visibility conditions.
+	Visibility Visibility `json:"visibility,omitempty"`
+
+	// RunwayVisualRange lists the observed visual range for specific runways.
+	RunwayVisualRange []RunwayVisualRange `json:"runway_visual_range,omitempty"`
+
+	// Weather lists information about the observed weather conditions.
+	Weather []Weather `json:"weather,omitempty"`
+
+	// RecentWeather lists weather conditions that have ended recently.
+	RecentWeather []Weather `json:"recent_weather,omitempty"`
+
+	// SkyCondition lists the observed sky conditions.
+	SkyCondition []SkyCondition `json:"sky_condition,omitempty"`
+
+	// Temperature holds the observed temperature in degrees Celsius.
+	Temperature *int `json:"temperature,omitempty"`
+
+	// Dewpoint holds the observed dewpoint in degrees Celsius.
+	Dewpoint *int `json:"dewpoint,omitempty"`
+
+	// Altimeter holds the observed altimeter setting.
+	Altimeter Altimeter `json:"altimeter,omitempty"`
+
+	// RunwayState lists the observed state of specific runways.
+	RunwayState []RunwayState `json:"runway_state,omitempty"`
+
+	// Trends lists the changes in conditions forecast for the next two hours.
+	Trends []*Trend `json:"trends,omitempty"`
+
+	// Flags contains special flags associated with the report.
+	Flags []Flag `json:"flags,omitempty"`
+
+	// Remark contains remarks from the report.
+	Remark string `json:"remark,omitempty"`
+
+	// Warnings lists the problems found while decoding the report in lenient mode.
+	Warnings []Problem `json:"warnings,omitempty"`
+
+	// Unparsed lists the groups that were skipped while decoding the report in lenient mode.
+	Unparsed []string `json:"unparsed,omitempty"`
+}
+
+// WindVariation represents the range of directions between which the wind is varying.
+type WindVariation struct {
+	// From is the first direction of the range, in degrees.
+	From int `json:"from,omitempty"`
+
+	// To is the last direction of the range, in degrees.
+	To int `json:"to,omitempty"`
+}
+
+// RVRTendency represents the tendency of a runway visual range.
+type RVRTendency string
+
+// RVR Tendencies
+const (
+	Upward   RVRTendency = "Upward"
+	Downward RVRTendency = "Downward"
+	NoChange RVRTendency = "NoChange"
+)
+
+// RunwayVisualRange represents the visual range along a specific runway.
+type RunwayVisualRange struct {
+	// Runway is the identifier of the runway, such as 24L.
+	Runway string `json:"runway,omitempty"`
+
+	// Visibility describes the visual range along the runway.
+	Visibility Visibility `json:"visibility,omitempty"`
+
+	// MaxVisibility describes the upper end of the visual range, if it's variable.
+	MaxVisibility Visibility `json:"max_visibility,omitempty"`
+
+	// Tendency indicates whether the visual range is increasing or decreasing.
+	Tendency RVRTendency `json:"tendency,omitempty"`
+}
+
+// RunwayDeposit represents the type of deposit on a runway.
+type RunwayDeposit string
+
+// Runway Deposits
+const (
+	ClearAndDry   RunwayDeposit = "ClearAndDry"
+	Damp          RunwayDeposit = "Damp"
+	WetOrPatches  RunwayDeposit = "WetOrPatches"
+	RimeOrFrost   RunwayDeposit = "RimeOrFrost"
+	DrySnow       RunwayDeposit = "DrySnow"
+	WetSnow       RunwayDeposit = "WetSnow"
+	Slush         RunwayDeposit = "Slush"
+	Ice           RunwayDeposit = "Ice"
+	CompactedSnow RunwayDeposit = "CompactedSnow"
+	FrozenRuts    RunwayDeposit = "FrozenRuts"
+)
+
+// BrakingAction represents the estimated braking action on a runway.
+type BrakingAction string
+
+// Braking Actions
+const (
+	BrakingPoor       BrakingAction = "Poor"
+	BrakingMediumPoor BrakingAction = "MediumPoor"
+	BrakingMedium     BrakingAction = "Medium"
+	BrakingMediumGood BrakingAction = "MediumGood"
+	BrakingGood       BrakingAction = "Good"
+	BrakingUnreliable BrakingAction = "Unreliable"
+)
+
+// RunwayState represents the state of a specific runway.
+type RunwayState struct {
+	// Runway is the identifier of the runway, such as 24L.
+	Runway string `json:"runway,omitempty"`
+
+	// Cleared indicates that contamination has been cleared from the runway.
+	Cleared bool `json:"cleared,omitempty"`
+
+	// Deposit indicates the type of deposit on the runway.
+	Deposit RunwayDeposit `json:"deposit,omitempty"`
+
+	// Coverage holds the maximum percentage of the runway covered by the deposit.
+	Coverage int `json:"coverage,omitempty"`
+
+	// Depth holds the depth of the deposit in millimeters.
+	Depth int `json:"depth,omitempty"`
+
+	// Friction holds the measured friction coefficient.
+	Friction float64 `json:"friction,omitempty"`
+
+	// BrakingAction holds the estimated braking action, if the friction wasn't measured.
+	BrakingAction BrakingAction `json:"braking_action,omitempty"`
+}
+
+// Trend represents an expected change in conditions appended to a METAR.
+type Trend struct {
+	// Type specifies the nature of this change.
+	Type ChangeType `json:"type,omitempty"`
+
+	// From indicates the time at which the change is expected to begin.
+	From time.Time `json:"from,omitempty"`
+
+	// Until indicates the time at which the change is expected to end.
+	Until time.Time `json:"until,omitempty"`
+
+	// At indicates the time at which the change is expected to happen.
+	At time.Time `json:"at,omitempty"`
+
+	// Visibility describes the anticipated visibility conditions.
+	Visibility Visibility `json:"visibility,omitempty"`
+
+	// Wind describes the projected wind conditions.
+	Wind Wind `json:"wind,omitempty"`
+
+	// SkyCondition lists the expected sky conditions.
+	SkyCondition []SkyCondition `json:"sky_condition,omitempty"`
+
+	// Weather lists information about the expected weather conditions.
+	Weather []Weather `json:"weather,omitempty"`
+
+	// Flags contains special flags associated with the change.
+	Flags []Flag `json:"flags,omitempty"`
+}
+
+// DecodeMETARString decodes a METAR string and returns a METAR.
+// This is equivalent to DecodeMETAR(strings.NewReader(s)).
+func DecodeMETARString(s string) (*METAR, error) {
+	return DecodeMETAR(strings.NewReader(s))
+}
+
+// DecodeMETARFile decodes a METAR string and returns a METAR.
+// This is equivalent to opening a file and passing it
+// to DecodeMETAR().
+func DecodeMETARFile(path string) (*METAR, error) {
+	fl, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fl.Close()
+
+	return DecodeMETAR(fl)
+}
+
+// DecodeMETAR decodes the data in a reader using default options and
+// returns a METAR
+func DecodeMETAR(r io.Reader) (*METAR, error) {
+	return DecodeMETARWithOptions(r, Options{})
+}
+
+// DecodeMETARWithOptions decodes the data in a reader and returns a METAR
+func DecodeMETARWithOptions(r io.Reader, opts Options) (*METAR, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	ps := &problems{}
+	ast, err := parseWithRecovery(parser.METARParser, readerName(r), string(data), ps)
+	if err != nil {
+		return nil, err
+	}
+
+	mt := &METAR{Special: strings.HasPrefix(ast.Header, "SPECI")}
+	out := reflect.ValueOf(mt).Elem()
+
+	var trend *Trend
+	for _, item := range ast.Items {
+		switch {
+		case item.ID != nil:
+			// The first identifier is the airport. Any other unknown
+			// group is a problem.
+			if mt.Identifier != "" {
+				ps.add(problemf(item.Pos, KindUnknown, "unrecognized group"))
+				continue
+			}
+
+			mt.Identifier = *item.ID
+			if a, ok := airports.Airports[mt.Identifier]; ok {
+				mt.Airport = a
+			}
+		case item.Time != nil:
+			t, err := parseIssueTime(*item.Time, opts)
+			if err != nil {
+				ps.add(problemf(item.Pos, KindTime, "%s", err))
+				continue
+			}
+			mt.ObservationTime = t
+		case item.WindSpeed != nil:
+			wind, err := parseWind(item.WindSpeed, opts)
+			if err != nil {
+				ps.add(err)
+				continue
+			}
+			setField(out, "Wind", wind)
+		case item.WindVariation != nil:
+			from, err := strconv.Atoi(item.WindVariation.From)
+			if err != nil {
+				ps.add(problemf(item.WindVariation.Pos, KindWind, "%s", err))
+				continue
+			}
+
+			to, err := strconv.Atoi(item.WindVariation.To)
+			if err != nil {
+				ps.add(problemf(item.WindVariation.Pos, KindWind, "%s", err))
+				continue
+			}
+
+			mt.WindVariation = WindVariation{From: from, To: to}
+		case item.Visibility != nil:
+			vis, err := parseVisibility(item.Visibility, opts)
+			if err != nil {
+				ps.add(err)
+				continue
+			}
+			setField(out, "Visibility", vis)
+		case item.RVR != nil:
+			rvr, err := parseRVR(*item.RVR, opts)
+			if err != nil {
+				ps.add(problemf(item.Pos, KindRVR, "%s", err))
+				continue
+			}
+			mt.RunwayVisualRange = append(mt.RunwayVisualRange, rvr)
+		case item.RunwayState != nil:
+			rs, err := parseRunwayState(*item.RunwayState)
+			if err != nil {
+				ps.add(problemf(item.Pos, KindRunwayState, "%s", err))
+				continue
+			}
+			mt.RunwayState = append(mt.RunwayState, rs)
+		case item.SkyCondition != nil:
+			sc, err := parseSkyCondition(item.SkyCondition, opts)
+			if err != nil {
+				ps.add(err)
+				continue
+			}
+			appendField(out, "SkyCondition", sc)
+		case item.Vicinity != nil:
+			appendField(out, "Weather", parseVicinity(item.Vicinity))
+		case item.Weather != nil:
+			appendField(out, "Weather", parseWeather(item.Weather))
+		case item.Descriptor != nil:
+			appendField(out, "Weather", Weather{
+				Modifier:   convertModifier(item.Descriptor.Modifier),
+				Descriptor: convertDescriptor(item.Descriptor.Descriptor),
+			})
+		case item.Recent != nil:
+			mt.RecentWeather = append(mt.RecentWeather, Weather{
+				Descriptor:    convertDescriptor(item.Recent.Descriptor),
+				Precipitation: convertPrecipitation(item.Recent.Precipitation),
+				Obscuration:   convertObscuration(item.Recent.Obscuration),
+				Phenomenon:    convertPhenomenon(item.Recent.Other),
+			})
+		case item.TempDew != nil:
+			temp, dew, err := parseTempDew(*item.TempDew)
+			if err != nil {
+				ps.add(problemf(item.Pos, KindTemperature, "%s", err))
+				continue
+			}
+			mt.Temperature, mt.Dewpoint = temp, dew
+		case item.Altimeter != nil:
+			alt, err := parseAltimeter(*item.Altimeter)
+			if err != nil {
+				ps.add(problemf(item.Pos, KindAltimeter, "%s", err))
+				continue
+			}
+			mt.Altimeter = opts.unitSystem().convertAltimeter(alt)
+		case item.Flag != nil:
+			switch {
+			case item.Flag.Auto:
+				mt.Flags = append(mt.Flags, Automated)
+			case item.Flag.Corrected:
+				mt.ReportType = Corrected
+			case item.Flag.CAVOK:
+				appendField(out, "Flags", CeilingAndVisibilityOK)
+			case item.Flag.NSW:
+				appendField(out, "Flags", NoSignificantWeather)
+			case item.Flag.NSC:
+				appendField(out, "Flags", NoSignificantCloud)
+			case item.Flag.NCD:
+				appendField(out, "Flags", NoCloudDetected)
+			case item.Flag.Nil:
+				mt.Flags = append(mt.Flags, Missing)
+			case item.Flag.NoSig:
+				mt.Flags = append(mt.Flags, NoSignificantChange)
+			}
+		case item.Trend != nil:
+			trend = &Trend{Type: convertChangeType(item.Trend.Type)}
+			mt.Trends = append(mt.Trends, trend)
+
+			// Set out to the trend value so that future mutations
+			// happen to the trend rather than the root report.
+			out = reflect.ValueOf(trend).Elem()
+		case item.TrendTime != nil:
+			if trend == nil {
+				ps.add(problemf(item.TrendTime.Pos, KindTrend, "%s time without a trend", item.TrendTime.Type))
+				continue
+			}
+
+			t, err := parseTrendTime(item.TrendTime.Time, mt.ObservationTime)
+			if err != nil {
+				ps.add(problemf(item.TrendTime.Pos, KindTrend, "%s", err))
+				continue
+			}
+
+			switch item.TrendTime.Type {
+			case "FM":
+				trend.From = t
+			case "TL":
+				trend.Until = t
+			case "AT":
+				trend.At = t
+			}
+		case item.Remark != nil:
+			mt.Remark = strings.TrimSpace(strings.TrimPrefix(*item.Remark, "RMK"))
+		}
+	}
+
+	if err := ps.result(opts.Lenient); err != nil {
+		return nil, err
+	}
+
+	mt.Warnings = ps.list
+	mt.Unparsed = ps.unparsed()
+	return mt, nil
+}
+
+var rvrRegex = regexp.MustCompile(`^R(\d{2}[LCR]?)/([PM]?)(\d{4})(?:V([PM]?)(\d{4}))?(FT)?/?([UDN]?)$`)
+
+// parseRVR parses a runway visual range group, such as R24L/1200FT
+func parseRVR(s string, opts Options) (RunwayVisualRange, error) {
+	m := rvrRegex.FindStringSubmatch(s)
+	if m == nil {
+		return RunwayVisualRange{}, fmt.Errorf("invalid group %q", s)
+	}
+
+	unit := units.Meters
+	if m[6] == "FT" {
+		unit = units.Feet
+	}
+
+	rvr := RunwayVisualRange{Runway: m[1]}
+
+	var err error
+	rvr.Visibility, err = parseRVRValue(m[2], m[3], unit, opts)
+	if err != nil {
+		return RunwayVisualRange{}, err
+	}
+
+	if m[5] != "" {
+		rvr.MaxVisibility, err = parseRVRValue(m[4], m[5], unit, opts)
+		if err != nil {
+			return RunwayVisualRange{}, err
+		}
+	}
+
+	switch m[7] {
+	case "U":
+		rvr.Tendency = Upward
+	case "D":
+		rvr.Tendency = Downward
+	case "N":
+		rvr.Tendency = NoChange
+	}
+
+	return rvr, nil
+}
+
+// parseRVRValue parses a single value of a runway visual range group,
+// converting it to opts.DistanceUnit if it's set.
+func parseRVRValue(prefix, s string, unit units.Distance, opts Options) (Visibility, error) {
+	val, err := strconv.Atoi(s)
+	if err != nil {
+		return Visibility{}, err
+	}
+
+	return opts.unitSystem().convertVisibility(Visibility{
+		Plus:   prefix == "P",
+		Minus:  prefix == "M",
+		Length: units.Length{Value: float64(val), Unit: unit},
+	}), nil
+}
+
+var runwayStateRegex = regexp.MustCompile(`^R(\d{2}[LCR]?)/(?:(CLRD)|([\d/])([\d/])([\d/]{2}))([\d/]{2})$`)
+
+// parseRunwayState parses a runway state group, such as R24L/490155
+func parseRunwayState(s string) (RunwayState, error) {
+	m := runwayStateRegex.FindStringSubmatch(s)
+	if m == nil {
+		return RunwayState{}, fmt.Errorf("invalid group %q", s)
+	}
+
+	rs := RunwayState{
+		Runway:  m[1],
+		Cleared: m[2] != "",
+		Deposit: convertRunwayDeposit(m[3]),
+	}
+
+	switch m[4] {
+	case "1":
+		rs.Coverage = 10
+	case "2":
+		rs.Coverage = 25
+	case "5":
+		rs.Coverage = 50
+	case "9":
+		rs.Coverage = 100
+	}
+
+	if depth, err := strconv.Atoi(m[5]); err == nil {
+		switch {
+		case depth <= 90:
+			rs.Depth = depth
+		case depth >= 92 && depth <= 98:
+			// Values 92-98 represent 10cm to 40cm in steps of 5cm
+			rs.Depth = (depth - 90) * 50
+		}
+	}
+
+	if friction, err := strconv.Atoi(m[6]); err == nil {
+		switch {
+		case friction <= 90:
+			rs.Friction = float64(friction) / 100
+		default:
+			rs.BrakingAction = convertBrakingAction(friction)
+		}
+	}
+
+	return rs, nil
+}
+
+// parseTempDew parses a temperature/dewpoint group, such as M05/M10.
+// The dewpoint may be missing, in which case it will be nil.
+func parseTempDew(s string) (temp, dew *int, err error) {
+	ts, ds, _ := strings.Cut(s, "/")
+
+	temp, err = parseMinusInt(ts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if ds != "" {
+		dew, err = parseMinusInt(ds)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return temp, dew, nil
+}
+
+// parseMinusInt parses an integer where a leading M indicates a negative value
+func parseMinusInt(s string) (*int, error) {
+	neg := strings.HasPrefix(s, "M")
+	val, err := strconv.Atoi(strings.TrimPrefix(s, "M"))
+	if err != nil {
+		return nil, err
+	}
+	if neg {
+		val = -val
+	}
+	return &val, nil
+}
+
+// parseAltimeter parses an altimeter group, such as A2992 or Q1013
+func parseAltimeter(s string) (Altimeter, error) {
+	val, err := strconv.Atoi(s[1:])
+	if err != nil {
+		return Altimeter{}, err
+	}
+
+	if s[0] == 'A' {
+		// Inches of mercury are given in hundredths
+		return Altimeter{Value: float64(val) / 100, Unit: units.InchesOfMercury}, nil
+	}
+	return Altimeter{Value: float64(val), Unit: units.Hectopascals}, nil
+}
+
+// parseTrendTime parses an HHMM trend time relative to the
+// observation time. Times earlier than the observation time
+// are assumed to be on the next day.
+func parseTrendTime(s string, obs time.Time) (time.Time, error) {
+	t, err := time.Parse("1504", s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	out := time.Date(obs.Year(), obs.Month(), obs.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	if out.Before(obs) {
+		out = out.AddDate(0, 0, 1)
+	}
+	return out, nil
+}
@@ -0,0 +1,148 @@
+package taf
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"go.elara.ws/taf/airports"
+	"go.elara.ws/taf/units"
+)
+
+func TestMETAR(t *testing.T) {
+	const data = `METAR COR EGLL 211750Z AUTO 22008G20KT 190V250 9999 R27L/P1500N R09R/0600V1000D
+  +TS VCSH SCT040 BKN100CB M05/M10 Q1013 RERA R27R/490155
+  BECMG FM1830 TL1900 25015KT TEMPO TSRA RMK TEST`
+
+	temp, dew := -5, -10
+
+	expected := &METAR{
+		ReportType: Corrected,
+		Identifier: "EGLL",
+		Airport: airports.Airport{
+			ICAO:      "EGLL",
+			IATA:      "LHR",
+			Name:      "London Heathrow Airport",
+			City:      "London",
+			State:     "England",
+			Country:   "GB",
+			Elevation: 83,
+			Latitude:  51.4706001282,
+			Longitude: -0.4619410038,
+			Timezone:  "Europe/London",
+		},
+		ObservationTime: time.Date(2023, time.August, 21, 17, 50, 0, 0, time.UTC),
+		Wind: Wind{
+			Direction: Direction{
+				Value: 220,
+			},
+			Speed: units.Velocity{Value: 8, Unit: units.Knots},
+			Gusts: units.Velocity{Value: 20, Unit: units.Knots},
+		},
+		WindVariation: WindVariation{
+			From: 190,
+			To:   250,
+		},
+		Visibility: Visibility{
+			Length: units.Length{Value: 9999, Unit: units.Meters},
+		},
+		RunwayVisualRange: []RunwayVisualRange{
+			{
+				Runway: "27L",
+				Visibility: Visibility{
+					Plus:   true,
+					Length: units.Length{Value: 1500, Unit: units.Meters},
+				},
+				Tendency: NoChange,
+			},
+			{
+				Runway: "09R",
+				Visibility: Visibility{
+					Length: units.Length{Value: 600, Unit: units.Meters},
+				},
+				MaxVisibility: Visibility{
+					Length: units.Length{Value: 1000, Unit: units.Meters},
+				},
+				Tendency: Downward,
+			},
+		},
+		Weather: []Weather{
+			{
+				Modifier:   Heavy,
+				Descriptor: Thunderstorm,
+			},
+			{
+				Vicinity:   true,
+				Descriptor: Showers,
+			},
+		},
+		RecentWeather: []Weather{
+			{
+				Precipitation: Rain,
+			},
+		},
+		SkyCondition: []SkyCondition{
+			{
+				Type:     Scattered,
+				Altitude: units.HeightFeet(4000),
+			},
+			{
+				Type:      Broken,
+				Altitude:  units.HeightFeet(10000),
+				CloudType: CumuloNimbus,
+			},
+		},
+		Temperature: &temp,
+		Dewpoint:    &dew,
+		Altimeter: Altimeter{
+			Value: 1013,
+			Unit:  units.Hectopascals,
+		},
+		RunwayState: []RunwayState{
+			{
+				Runway:   "27R",
+				Deposit:  DrySnow,
+				Coverage: 100,
+				Depth:    1,
+				Friction: 0.55,
+			},
+		},
+		Trends: []*Trend{
+			{
+				Type:  Becoming,
+				From:  time.Date(2023, time.August, 21, 18, 30, 0, 0, time.UTC),
+				Until: time.Date(2023, time.August, 21, 19, 0, 0, 0, time.UTC),
+				Wind: Wind{
+					Direction: Direction{
+						Value: 250,
+					},
+					Speed: units.Velocity{Value: 15, Unit: units.Knots},
+				},
+			},
+			{
+				Type: Temporary,
+				Weather: []Weather{
+					{
+						Descriptor:    Thunderstorm,
+						Precipitation: Rain,
+					},
+				},
+			},
+		},
+		Flags:  []Flag{Automated},
+		Remark: "TEST",
+	}
+
+	mt, err := DecodeMETARWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	if diff := deep.Equal(mt, expected); diff != nil {
+		t.Error(diff)
+	}
+}
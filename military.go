@@ -0,0 +1,224 @@
+package taf
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.elara.ws/taf/units"
+)
+
+// Intensity represents the intensity of icing or turbulence.
+type Intensity string
+
+// Intensities
+const (
+	IntensityNone     Intensity = "None"
+	IntensityLight    Intensity = "Light"
+	IntensityModerate Intensity = "Moderate"
+	IntensitySevere   Intensity = "Severe"
+)
+
+// Icing represents a layer of icing, as given by the 6IHHHT
+// groups in US military TAFs.
+type Icing struct {
+	// Intensity specifies how severe the icing is expected to be.
+	Intensity Intensity `json:"intensity,omitempty"`
+
+	// InCloud indicates that the icing is expected within clouds.
+	InCloud bool `json:"in_cloud,omitempty"`
+
+	// InPrecipitation indicates that the icing is expected within precipitation.
+	InPrecipitation bool `json:"in_precipitation,omitempty"`
+
+	// Base holds the height of the base of the layer.
+	Base units.Height `json:"base"`
+
+	// Thickness holds the thickness of the layer.
+	Thickness units.Height `json:"thickness"`
+}
+
+// Turbulence represents a layer of turbulence, as given by the
+// 5BHHHT groups in US military TAFs.
+type Turbulence struct {
+	// Intensity specifies how severe the turbulence is expected to be.
+	Intensity Intensity `json:"intensity,omitempty"`
+
+	// InCloud indicates that the turbulence is expected within clouds
+	// rather than in clear air.
+	InCloud bool `json:"in_cloud,omitempty"`
+
+	// Frequent indicates that the turbulence is expected to be
+	// frequent rather than occasional.
+	Frequent bool `json:"frequent,omitempty"`
+
+	// Base holds the height of the base of the layer.
+	Base units.Height `json:"base"`
+
+	// Thickness holds the thickness of the layer.
+	Thickness units.Height `json:"thickness"`
+}
+
+// splitLayer splits a 6-digit icing or turbulence group
+// into its code, base, and thickness.
+func splitLayer(s string) (code int, base, thickness units.Height, err error) {
+	if len(s) != 6 {
+		return 0, base, thickness, fmt.Errorf("invalid length (%d)", len(s))
+	}
+
+	code, err = strconv.Atoi(s[1:2])
+	if err != nil {
+		return 0, base, thickness, err
+	}
+
+	// The base is given in hundreds of feet
+	hundreds, err := strconv.Atoi(s[2:5])
+	if err != nil {
+		return 0, base, thickness, err
+	}
+
+	// The thickness is given in thousands of feet
+	thousands, err := strconv.Atoi(s[5:6])
+	if err != nil {
+		return 0, base, thickness, err
+	}
+
+	return code, units.HeightFeet(float64(hundreds * 100)), units.HeightFeet(float64(thousands * 1000)), nil
+}
+
+// parseIcing parses an icing group, such as 620304, converting
+// its heights to opts.HeightUnit if it's set.
+func parseIcing(s string, opts Options) (Icing, error) {
+	code, base, thickness, err := splitLayer(s)
+	if err != nil {
+		return Icing{}, err
+	}
+
+	us := opts.unitSystem()
+	out := Icing{Base: us.convertHeight(base), Thickness: us.convertHeight(thickness)}
+
+	// Codes 1-9 are grouped in threes by intensity, and the position
+	// within the group specifies where the icing occurs.
+	switch {
+	case code == 0:
+		out.Intensity = IntensityNone
+		return out, nil
+	case code <= 3:
+		out.Intensity = IntensityLight
+	case code <= 6:
+		out.Intensity = IntensityModerate
+	default:
+		out.Intensity = IntensitySevere
+	}
+
+	switch (code - 1) % 3 {
+	case 1:
+		out.InCloud = true
+	case 2:
+		out.InPrecipitation = true
+	}
+
+	return out, nil
+}
+
+// parseTurbulence parses a turbulence group, such as 530005,
+// converting its heights to opts.HeightUnit if it's set.
+func parseTurbulence(s string, opts Options) (Turbulence, error) {
+	code, base, thickness, err := splitLayer(s)
+	if err != nil {
+		return Turbulence{}, err
+	}
+
+	us := opts.unitSystem()
+	out := Turbulence{Base: us.convertHeight(base), Thickness: us.convertHeight(thickness)}
+
+	switch {
+	case code == 0:
+		out.Intensity = IntensityNone
+		return out, nil
+	case code == 1:
+		out.Intensity = IntensityLight
+		return out, nil
+	case code <= 5:
+		out.Intensity = IntensityModerate
+	default:
+		out.Intensity = IntensitySevere
+	}
+
+	// Codes 2-9 are grouped in fours by intensity. The first two are
+	// in clear air and the last two are in cloud. Odd codes are frequent.
+	out.InCloud = (code-2)%4 >= 2
+	out.Frequent = code%2 == 1
+
+	return out, nil
+}
+
+// parseQNH parses a QNH group, such as QNH2992INS
+func parseQNH(value string, inches bool) (Altimeter, error) {
+	val, err := strconv.Atoi(value)
+	if err != nil {
+		return Altimeter{}, err
+	}
+
+	if inches {
+		// Inches of mercury are given in hundredths
+		return Altimeter{Value: float64(val) / 100, Unit: units.InchesOfMercury}, nil
+	}
+	return Altimeter{Value: float64(val), Unit: units.Hectopascals}, nil
+}
+
+// encodeIcing encodes an icing layer as a 6IHHHT group
+func encodeIcing(ic Icing) string {
+	code := 0
+	switch ic.Intensity {
+	case IntensityLight:
+		code = 1
+	case IntensityModerate:
+		code = 4
+	case IntensitySevere:
+		code = 7
+	}
+
+	if code != 0 {
+		switch {
+		case ic.InCloud:
+			code++
+		case ic.InPrecipitation:
+			code += 2
+		}
+	}
+
+	return fmt.Sprintf("6%d%03d%d", code, hundredsOfFeet(ic.Base), thousandsOfFeet(ic.Thickness))
+}
+
+// encodeTurbulence encodes a turbulence layer as a 5BHHHT group
+func encodeTurbulence(tb Turbulence) string {
+	code := 0
+	switch tb.Intensity {
+	case IntensityLight:
+		code = 1
+	case IntensityModerate:
+		code = 2
+	case IntensitySevere:
+		code = 6
+	}
+
+	if code > 1 {
+		if tb.InCloud {
+			code += 2
+		}
+		if tb.Frequent {
+			code++
+		}
+	}
+
+	return fmt.Sprintf("5%d%03d%d", code, hundredsOfFeet(tb.Base), thousandsOfFeet(tb.Thickness))
+}
+
+// encodeQNH encodes an altimeter setting as a QNH group
+func encodeQNH(alt Altimeter) string {
+	if alt.Unit == units.InchesOfMercury {
+		return fmt.Sprintf("QNH%04dINS", int(alt.Value*100+0.5))
+	}
+	// Anything other than inches of mercury is written in hectopascals
+	return fmt.Sprintf("QNH%04d", int(alt.Unit.Convert(units.Hectopascals, alt.Value)+0.5))
+}
@@ -0,0 +1,120 @@
+package taf
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"go.elara.ws/taf/units"
+)
+
+func TestMilitary(t *testing.T) {
+	const data = `TAF KADW 211400Z 2114/2220 18010KT 9999 BKN030 WS015/25045KT 620304 530005 QNH2992INS TX25/2118Z TNM02/2210Z
+  BECMG 2200/2202 VRB06KT 8000 -RA OVC015 640108 QNH2985INS`
+
+	fc, err := DecodeWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	if fc.Wind.Speed.Value != 10 {
+		t.Errorf("Expected wind shear not to replace the surface wind, got %v", fc.Wind)
+	}
+
+	expectedShear := Wind{Direction: Direction{Value: 250}, WindShear: units.HeightFeet(1500), Speed: units.Velocity{Value: 45, Unit: units.Knots}}
+	if diff := deep.Equal(fc.WindShear, expectedShear); diff != nil {
+		t.Error(diff)
+	}
+
+	if diff := deep.Equal(fc.Icing, []Icing{{Intensity: IntensityLight, InCloud: true, Base: units.HeightFeet(3000), Thickness: units.HeightFeet(4000)}}); diff != nil {
+		t.Error(diff)
+	}
+
+	if diff := deep.Equal(fc.Turbulence, []Turbulence{{Intensity: IntensityModerate, Frequent: true, Base: units.HeightFeet(0), Thickness: units.HeightFeet(5000)}}); diff != nil {
+		t.Error(diff)
+	}
+
+	if diff := deep.Equal(fc.Altimeter, Altimeter{Value: 29.92, Unit: units.InchesOfMercury}); diff != nil {
+		t.Error(diff)
+	}
+
+	if fc.Temperature[1].Value != -2 || fc.Temperature[1].Type != Low {
+		t.Errorf("Expected low temperature of -2, got %v", fc.Temperature[1])
+	}
+
+	ch := fc.Changes[0]
+	if diff := deep.Equal(ch.Icing, []Icing{{Intensity: IntensityModerate, Base: units.HeightFeet(1000), Thickness: units.HeightFeet(8000)}}); diff != nil {
+		t.Error(diff)
+	}
+
+	if ch.Altimeter.Value != 29.85 {
+		t.Errorf("Expected change altimeter of 29.85, got %v", ch.Altimeter.Value)
+	}
+
+	if fc.String() != data {
+		t.Errorf("Expected encoded forecast to match input, got %q", fc.String())
+	}
+}
+
+func TestMilitaryTemperature(t *testing.T) {
+	fc, err := DecodeWithOptions(strings.NewReader("KADW 211400Z 2114/2220 18010KT 9999 SKC T25/18Z TN12/10Z"), Options{
+		Month: time.August,
+		Year:  2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	expected := []Temperature{
+		{Type: High, Value: 25, Unit: units.Celsius, Time: time.Date(2023, time.August, 21, 18, 0, 0, 0, time.UTC)},
+		{Type: Low, Value: 12, Unit: units.Celsius, Time: time.Date(2023, time.August, 22, 10, 0, 0, 0, time.UTC)},
+	}
+	if diff := deep.Equal(fc.Temperature, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestMilitaryConvertUnits(t *testing.T) {
+	const data = `TAF KADW 211400Z 2114/2220 18010KT 9999 BKN030 WS015/25045KT 620304 QNH2992INS TX25/2118Z TNM02/2210Z`
+
+	fc, err := DecodeWithOptions(strings.NewReader(data), Options{
+		TemperatureUnit: units.Fahrenheit,
+		PressureUnit:    units.Hectopascals,
+		HeightUnit:      units.HeightInMeters,
+		Month:           time.August,
+		Year:            2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	if tx, tn := fc.Temperature[0], fc.Temperature[1]; tx.Value != 77 || tn.Value != 28 || tx.Unit != units.Fahrenheit {
+		t.Errorf("Expected temperatures of 77°F and 28°F, got %v and %v", tx, tn)
+	}
+
+	if fc.Altimeter.Unit != units.Hectopascals || math.Round(fc.Altimeter.Value) != 1013 {
+		t.Errorf("Expected altimeter of 1013 hPa, got %v", fc.Altimeter)
+	}
+
+	if alt := fc.SkyCondition[0].Altitude; alt.Unit != units.HeightInMeters || math.Abs(alt.Value-914.4) > 1e-9 {
+		t.Errorf("Expected cloud base of 914.4 m, got %s", alt)
+	}
+
+	if ws := fc.WindShear.WindShear; ws.Unit != units.HeightInMeters || math.Abs(ws.Value-457.2) > 1e-9 {
+		t.Errorf("Expected wind shear at 457.2 m, got %s", ws)
+	}
+
+	if ic := fc.Icing[0]; ic.Base.Unit != units.HeightInMeters || math.Abs(ic.Base.Value-914.4) > 1e-9 || math.Abs(ic.Thickness.Value-1219.2) > 1e-9 {
+		t.Errorf("Expected icing from 914.4 m, 1219.2 m thick, got %s and %s", ic.Base, ic.Thickness)
+	}
+
+	// Temperatures and heights are converted back when encoding
+	if enc := fc.String(); !strings.Contains(enc, "BKN030 WS015/25045KT 620304 QNH1013 TX25/2118Z TNM02/2210Z") {
+		t.Errorf("Expected encoded forecast to use the original units, got %q", enc)
+	}
+}
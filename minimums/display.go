@@ -0,0 +1,8 @@
+package minimums
+
+import "go.elara.ws/taf/i18n"
+
+// DisplayName returns the name of the treatment in the given locale
+func (t Treatment) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "Treatment", string(t))
+}
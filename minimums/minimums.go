@@ -0,0 +1,343 @@
+// Package minimums evaluates TAF forecasts against ceiling and visibility
+// minimums, such as the US 1-2-3 rule used to decide whether a destination
+// requires an alternate, and the standard alternate minimums.
+package minimums
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.elara.ws/taf"
+	"go.elara.ws/taf/units"
+)
+
+// Rule describes ceiling and visibility minimums.
+type Rule struct {
+	// Name is the name of the rule, such as "600-2".
+	Name string `json:"name"`
+
+	// Ceiling is the minimum ceiling.
+	Ceiling units.Height `json:"ceiling"`
+
+	// Visibility is the minimum visibility.
+	Visibility taf.Visibility `json:"visibility"`
+}
+
+// OneTwoThree is the US 1-2-3 rule. An alternate is required unless the
+// ceiling is at least 2000 ft and the visibility is at least 3 statute
+// miles from one hour before to one hour after the estimated time of arrival.
+var OneTwoThree = Rule{
+	Name:       "1-2-3",
+	Ceiling:    units.HeightFeet(2000),
+	Visibility: taf.Visibility{Length: units.Length{Value: 3, Unit: units.Miles}},
+}
+
+// Precision contains the standard alternate minimums for
+// airports with a precision approach.
+var Precision = Rule{
+	Name:       "600-2",
+	Ceiling:    units.HeightFeet(600),
+	Visibility: taf.Visibility{Length: units.Length{Value: 2, Unit: units.Miles}},
+}
+
+// NonPrecision contains the standard alternate minimums for
+// airports with only non-precision approaches.
+var NonPrecision = Rule{
+	Name:       "800-2",
+	Ceiling:    units.HeightFeet(800),
+	Visibility: taf.Visibility{Length: units.Length{Value: 2, Unit: units.Miles}},
+}
+
+// Treatment specifies how conditions from TEMPO and PROB groups are treated.
+type Treatment string
+
+// Treatments
+const (
+	// Limiting means that the conditions have to meet the minimums
+	// for the evaluation to pass. This is the default.
+	Limiting Treatment = "limiting"
+	// Advisory means that conditions below the minimums are reported
+	// as advisories, but don't cause the evaluation to fail.
+	Advisory Treatment = "advisory"
+	// Ignore means that the conditions aren't evaluated.
+	Ignore Treatment = "ignore"
+)
+
+// Options contains options for Evaluate.
+type Options struct {
+	// Temporary specifies how TEMPO groups and BECMG groups whose
+	// transition period is in progress are treated. If it's empty,
+	// Limiting is used.
+	Temporary Treatment
+
+	// Probability specifies how PROB groups are treated, including
+	// PROB TEMPO groups. If it's empty, Limiting is used.
+	Probability Treatment
+}
+
+// Result is the result of evaluating a forecast against a rule.
+type Result struct {
+	// Rule is the rule the forecast was evaluated against.
+	Rule Rule `json:"rule"`
+
+	// Window is the period in which the forecast was evaluated.
+	Window taf.ValidPair `json:"window"`
+
+	// Pass indicates whether the forecast meets the minimums
+	// throughout the window.
+	Pass bool `json:"pass"`
+
+	// Failures lists the groups that caused the evaluation to fail.
+	Failures []Failure `json:"failures,omitempty"`
+
+	// Advisories lists the groups that are below the minimums,
+	// but were treated as advisory.
+	Advisories []Failure `json:"advisories,omitempty"`
+}
+
+// Failure describes conditions that don't meet a rule's minimums.
+type Failure struct {
+	// Location identifies the group that produced the conditions
+	// (e.g. "changes[1]"). It's empty for the base forecast.
+	Location string `json:"location,omitempty"`
+
+	// Type is the type of the change that produced the conditions.
+	// It's empty for the base forecast and standalone PROB groups.
+	Type taf.ChangeType `json:"type,omitempty"`
+
+	// Probability is the probability of the conditions, if the group had one.
+	Probability int `json:"probability,omitempty"`
+
+	// Valid is the part of the window in which the conditions apply.
+	Valid taf.ValidPair `json:"valid"`
+
+	// Ceiling is the forecast ceiling. It's zero if there's no ceiling.
+	Ceiling units.Height `json:"ceiling"`
+
+	// Visibility is the forecast visibility.
+	Visibility taf.Visibility `json:"visibility,omitempty"`
+
+	// Reason describes why the conditions don't meet the minimums.
+	Reason string `json:"reason"`
+}
+
+// Window returns the period from one hour before to one hour after eta
+func Window(eta time.Time) taf.ValidPair {
+	return taf.ValidPair{
+		From:     eta.Add(-time.Hour),
+		To:       eta.Add(time.Hour),
+		Duration: 2 * time.Hour,
+	}
+}
+
+// RequiresAlternate evaluates the forecast for a destination against the
+// 1-2-3 rule and reports whether an alternate is required for the given
+// estimated time of arrival.
+func RequiresAlternate(fc *taf.Forecast, eta time.Time, opts Options) (bool, Result) {
+	res := Evaluate(fc, Window(eta), opts, OneTwoThree)[0]
+	return !res.Pass, res
+}
+
+// Evaluate checks the conditions forecast during the window against each of
+// the rules. The evaluation of a rule fails if any conditions don't meet its
+// minimums, or if the forecast doesn't cover the whole window.
+func Evaluate(fc *taf.Forecast, window taf.ValidPair, opts Options, rules ...Rule) []Result {
+	out := make([]Result, len(rules))
+	for i, rule := range rules {
+		out[i] = evaluate(fc, window, opts, rule)
+	}
+	return out
+}
+
+// evaluate checks the conditions forecast during the window against a rule
+func evaluate(fc *taf.Forecast, window taf.ValidPair, opts Options, rule Rule) Result {
+	res := Result{Rule: rule, Window: window, Pass: true}
+
+	if slices.Contains(fc.Flags, taf.Missing) || slices.Contains(fc.Flags, taf.Cancelled) ||
+		window.From.Before(fc.Valid.From) || window.To.After(fc.Valid.To) {
+		res.Pass = false
+		res.Failures = append(res.Failures, Failure{
+			Valid:  window,
+			Reason: "forecast doesn't cover the whole window",
+		})
+		return res
+	}
+
+	times := boundaries(fc, window)
+	for i, t := range times {
+		end := window.To
+		if i+1 < len(times) {
+			end = times[i+1]
+		}
+		segment := taf.ValidPair{From: t, To: end, Duration: end.Sub(t)}
+
+		c := fc.At(t)
+		if f, ok := check(rule, c.Visibility, c.SkyCondition, c.Flags); !ok {
+			f.Location = prevailingLocation(fc, t)
+			f.Valid = segment
+			res.Failures = addFailure(res.Failures, f)
+		}
+
+		for _, alt := range c.Alternates {
+			treatment := opts.Temporary
+			if alt.Probability != 0 {
+				treatment = opts.Probability
+			}
+
+			if treatment == Ignore {
+				continue
+			}
+
+			f, ok := check(rule, alt.Visibility, alt.SkyCondition, alt.Flags)
+			if ok {
+				continue
+			}
+
+			f.Location = alternateLocation(fc, alt)
+			f.Type = alt.Type
+			f.Probability = alt.Probability
+			f.Valid = segment
+
+			if treatment == Advisory {
+				res.Advisories = addFailure(res.Advisories, f)
+			} else {
+				res.Failures = addFailure(res.Failures, f)
+			}
+		}
+	}
+
+	res.Pass = len(res.Failures) == 0
+	return res
+}
+
+// check checks whether the given conditions meet the rule's minimums.
+// If they don't, it returns a failure describing why.
+func check(rule Rule, vis taf.Visibility, sky []taf.SkyCondition, flags []taf.Flag) (Failure, bool) {
+	// CAVOK implies a visibility of 10 km or more and no ceiling
+	if slices.Contains(flags, taf.CeilingAndVisibilityOK) {
+		return Failure{}, true
+	}
+
+	f := Failure{Visibility: vis}
+	var reasons []string
+
+	c := taf.Conditions{SkyCondition: sky}
+	if alt, ok := c.Ceiling(); ok {
+		f.Ceiling = alt
+		if alt.Less(rule.Ceiling) {
+			reasons = append(reasons, fmt.Sprintf("ceiling %s is below %s", alt, rule.Ceiling))
+		}
+	}
+
+	if vis.Unit == "" {
+		reasons = append(reasons, "no visibility forecast")
+	} else {
+		val := vis.In(rule.Visibility.Unit)
+		if val < rule.Visibility.Value || (vis.Minus && val <= rule.Visibility.Value) {
+			reasons = append(reasons, fmt.Sprintf(
+				"visibility %s %s is below %s %s",
+				formatFloat(val), rule.Visibility.Unit,
+				formatFloat(rule.Visibility.Value), rule.Visibility.Unit,
+			))
+		}
+	}
+
+	if len(reasons) == 0 {
+		return Failure{}, true
+	}
+
+	f.Reason = reasons[0]
+	for _, r := range reasons[1:] {
+		f.Reason += ", and " + r
+	}
+	return f, false
+}
+
+// addFailure adds f to failures, extending the previous failure
+// instead if it's the same and ends right where f starts.
+func addFailure(failures []Failure, f Failure) []Failure {
+	for i := len(failures) - 1; i >= 0; i-- {
+		prev := &failures[i]
+		if prev.Location == f.Location && prev.Reason == f.Reason && prev.Valid.To.Equal(f.Valid.From) {
+			prev.Valid.To = f.Valid.To
+			prev.Valid.Duration = prev.Valid.To.Sub(prev.Valid.From)
+			return failures
+		}
+	}
+	return append(failures, f)
+}
+
+// boundaries returns the start of the window, followed by the start and end
+// times of every group that are within the window, in chronological order.
+func boundaries(fc *taf.Forecast, window taf.ValidPair) []time.Time {
+	out := []time.Time{window.From}
+	add := func(vp taf.ValidPair) {
+		for _, t := range []time.Time{vp.From, vp.To} {
+			if t.After(window.From) && t.Before(window.To) {
+				out = append(out, t)
+			}
+		}
+	}
+
+	for _, ch := range fc.Changes {
+		add(ch.Valid)
+	}
+	for _, pr := range fc.Probabilities {
+		add(pr.Valid)
+	}
+
+	slices.SortFunc(out, func(a, b time.Time) int { return a.Compare(b) })
+	return slices.CompactFunc(out, time.Time.Equal)
+}
+
+// prevailingLocation returns the location of the latest FM or completed
+// BECMG group at t, which is the last group to change the prevailing
+// conditions. If there's no such group, it returns an empty string.
+func prevailingLocation(fc *taf.Forecast, t time.Time) string {
+	loc := ""
+	for i, ch := range fc.Changes {
+		switch {
+		case ch.Type == taf.From && !t.Before(ch.Valid.From),
+			ch.Type == taf.Becoming && !t.Before(ch.Valid.To):
+			loc = changeLocation(i)
+		}
+	}
+	return loc
+}
+
+// alternateLocation returns the location of the group that produced an alternate
+func alternateLocation(fc *taf.Forecast, alt taf.Alternate) string {
+	if alt.Type == "" {
+		for i, pr := range fc.Probabilities {
+			if pr.Value == alt.Probability && sameValid(pr.Valid, alt.Valid) {
+				return "probabilities[" + strconv.Itoa(i) + "]"
+			}
+		}
+		return ""
+	}
+
+	for i, ch := range fc.Changes {
+		if ch.Type == alt.Type && ch.Probability == alt.Probability && sameValid(ch.Valid, alt.Valid) {
+			return changeLocation(i)
+		}
+	}
+	return ""
+}
+
+// changeLocation returns the location of the change at index i
+func changeLocation(i int) string {
+	return "changes[" + strconv.Itoa(i) + "]"
+}
+
+// sameValid checks whether two valid pairs describe the same period
+func sameValid(a, b taf.ValidPair) bool {
+	return a.From.Equal(b.From) && a.To.Equal(b.To)
+}
+
+// formatFloat formats a float with at most two decimal places
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,152 @@
+package minimums
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"go.elara.ws/taf"
+	"go.elara.ws/taf/units"
+)
+
+const data = `TAF KJFK 211720Z 2118/2224 22008KT P6SM BKN040
+  FM220000 18010KT 4SM BR BKN015
+  TEMPO 2202/2204 1 1/2SM BR OVC007
+  PROB30 2204/2206 1/2SM FG VV002
+  FM220900 20012KT P6SM SCT030`
+
+func decode(t *testing.T) *taf.Forecast {
+	t.Helper()
+
+	fc, err := taf.DecodeWithOptions(strings.NewReader(data), taf.Options{
+		Month: time.August,
+		Year:  2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+	return fc
+}
+
+func at(day, hour int) time.Time {
+	return time.Date(2023, time.August, day, hour, 0, 0, 0, time.UTC)
+}
+
+func period(from, to time.Time) taf.ValidPair {
+	return taf.ValidPair{From: from, To: to, Duration: to.Sub(from)}
+}
+
+func TestRequiresAlternate(t *testing.T) {
+	fc := decode(t)
+
+	required, _ := RequiresAlternate(fc, at(21, 20), Options{})
+	if required {
+		t.Error("Expected no alternate to be required")
+	}
+
+	required, res := RequiresAlternate(fc, at(22, 0), Options{})
+	if !required {
+		t.Fatal("Expected an alternate to be required")
+	}
+
+	expected := []Failure{{
+		Location:   "changes[0]",
+		Valid:      period(at(22, 0), at(22, 1)),
+		Ceiling:    units.HeightFeet(1500),
+		Visibility: taf.Visibility{Length: units.Length{Value: 4, Unit: units.Miles}},
+		Reason:     "ceiling 1500 ft is below 2000 ft",
+	}}
+
+	if diff := deep.Equal(res.Failures, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	required, res = RequiresAlternate(fc, at(23, 0), Options{})
+	if !required || res.Failures[0].Reason != "forecast doesn't cover the whole window" {
+		t.Errorf("Expected the window outside the forecast to fail, got %+v", res)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	fc := decode(t)
+
+	tempo := Failure{
+		Location:   "changes[1]",
+		Type:       taf.Temporary,
+		Valid:      period(at(22, 2), at(22, 4)),
+		Ceiling:    units.HeightFeet(700),
+		Visibility: taf.Visibility{Length: units.Length{Value: 1.5, Unit: units.Miles}},
+		Reason:     "visibility 1.5 Miles is below 2 Miles",
+	}
+
+	prob := Failure{
+		Location:    "probabilities[0]",
+		Probability: 30,
+		Valid:       period(at(22, 4), at(22, 6)),
+		Ceiling:     units.HeightFeet(200),
+		Visibility:  taf.Visibility{Length: units.Length{Value: 0.5, Unit: units.Miles}},
+		Reason:      "ceiling 200 ft is below 800 ft, and visibility 0.5 Miles is below 2 Miles",
+	}
+
+	testCases := []struct {
+		name     string
+		eta      time.Time
+		opts     Options
+		rule     Rule
+		expected Result
+	}{
+		{
+			name: "tempo limiting",
+			eta:  at(22, 3),
+			rule: Precision,
+			expected: Result{
+				Rule:     Precision,
+				Window:   Window(at(22, 3)),
+				Failures: []Failure{tempo},
+			},
+		},
+		{
+			name: "tempo advisory",
+			eta:  at(22, 3),
+			opts: Options{Temporary: Advisory},
+			rule: Precision,
+			expected: Result{
+				Rule:       Precision,
+				Window:     Window(at(22, 3)),
+				Pass:       true,
+				Advisories: []Failure{tempo},
+			},
+		},
+		{
+			name: "prob limiting",
+			eta:  at(22, 5),
+			rule: NonPrecision,
+			expected: Result{
+				Rule:     NonPrecision,
+				Window:   Window(at(22, 5)),
+				Failures: []Failure{prob},
+			},
+		},
+		{
+			name: "prob ignored",
+			eta:  at(22, 5),
+			opts: Options{Probability: Ignore},
+			rule: NonPrecision,
+			expected: Result{
+				Rule:   NonPrecision,
+				Window: Window(at(22, 5)),
+				Pass:   true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res := Evaluate(fc, Window(tc.eta), tc.opts, tc.rule)
+			if diff := deep.Equal(res, []Result{tc.expected}); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
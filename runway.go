@@ -0,0 +1,213 @@
+package taf
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.elara.ws/taf/airports"
+	"go.elara.ws/taf/units"
+)
+
+// Runway represents a runway direction at an airport.
+type Runway struct {
+	// Identifier is the runway designator, such as "24L".
+	Identifier string `json:"identifier,omitempty"`
+
+	// Heading is the heading of the runway in degrees.
+	Heading int `json:"heading,omitempty"`
+
+	// Magnetic indicates whether Heading is relative to magnetic north
+	// rather than true north.
+	Magnetic bool `json:"magnetic,omitempty"`
+
+	// Variation is the magnetic variation at the airport in degrees,
+	// positive to the east. It's used to convert magnetic headings to
+	// true headings.
+	Variation int `json:"variation,omitempty"`
+}
+
+// ParseRunway creates a runway from a designator such as "24L" or "RWY09".
+// The heading is derived from the runway number, so it's magnetic, and the
+// variation is zero. Set the variation if it's known, or the heading if a
+// more precise value is available.
+func ParseRunway(id string) (Runway, bool) {
+	id = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(id)), "RWY")
+
+	num := strings.TrimRight(id, "LRC")
+	if len(id)-len(num) > 1 || len(num) == 0 || len(num) > 2 {
+		return Runway{}, false
+	}
+
+	n, err := strconv.Atoi(num)
+	if err != nil || n < 1 || n > 36 {
+		return Runway{}, false
+	}
+
+	return Runway{Identifier: id, Heading: n * 10, Magnetic: true}, true
+}
+
+// Runways returns the runways at the forecast's airport from the airports
+// dataset. If the true heading of a runway isn't known, it's derived from
+// the runway's designator instead, so it's magnetic.
+func (fc *Forecast) Runways() []Runway {
+	return airportRunways(fc.Airport.Runways())
+}
+
+// airportRunways converts runway ends from the airports dataset
+func airportRunways(rwys []airports.Runway) []Runway {
+	var out []Runway
+	for _, rwy := range rwys {
+		if rwy.Heading != 0 {
+			out = append(out, Runway{
+				Identifier: rwy.Ident,
+				Heading:    normalizeHeading(int(math.Round(rwy.Heading))),
+			})
+		} else if r, ok := ParseRunway(rwy.Ident); ok {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// TrueHeading returns the heading of the runway relative to true north
+func (r Runway) TrueHeading() int {
+	if !r.Magnetic {
+		return r.Heading
+	}
+	return normalizeHeading(r.Heading + r.Variation)
+}
+
+// WindComponents contains the components of a wind relative to a runway.
+type WindComponents struct {
+	// Headwind is the component of the sustained wind along the runway.
+	// A negative value indicates a tailwind.
+	Headwind int `json:"headwind"`
+
+	// Crosswind is the component of the sustained wind across the runway.
+	// A positive value indicates wind from the right, and a negative value
+	// indicates wind from the left.
+	Crosswind int `json:"crosswind"`
+
+	// GustHeadwind is the component of the gusts along the runway.
+	// It's zero if there are no gusts.
+	GustHeadwind int `json:"gust_headwind,omitempty"`
+
+	// GustCrosswind is the component of the gusts across the runway.
+	// It's zero if there are no gusts.
+	GustCrosswind int `json:"gust_crosswind,omitempty"`
+
+	// Unit is the unit of the components.
+	Unit units.Speed `json:"unit,omitempty"`
+}
+
+// MaxCrosswind returns the larger of the sustained
+// and gust crosswind, regardless of direction.
+func (wc WindComponents) MaxCrosswind() int {
+	return max(abs(wc.Crosswind), abs(wc.GustCrosswind))
+}
+
+// MaxTailwind returns the larger of the sustained and gust
+// tailwind. If there's no tailwind, it returns zero.
+func (wc WindComponents) MaxTailwind() int {
+	return max(-wc.Headwind, -wc.GustHeadwind, 0)
+}
+
+// Components calculates the headwind and crosswind components of the wind
+// for a runway with the given true heading. Wind directions in TAF reports
+// are relative to true north, so use Runway.TrueHeading for magnetic headings.
+//
+// If the wind direction is variable, the worst case is assumed for each
+// component: the whole wind is counted as a crosswind from the right and
+// as a tailwind.
+func (w Wind) Components(runwayHeading int) WindComponents {
+	out := WindComponents{Unit: w.Speed.Unit}
+	speed, gusts := w.Speed.Value, w.Gusts.In(w.Speed.Unit)
+
+	if w.Direction.Variable {
+		out.Headwind, out.Crosswind = -int(math.Round(speed)), int(math.Round(speed))
+		out.GustHeadwind, out.GustCrosswind = -int(math.Round(gusts)), int(math.Round(gusts))
+		return out
+	}
+
+	angle := float64(w.Direction.Value-runwayHeading) * math.Pi / 180
+	sin, cos := math.Sincos(angle)
+
+	out.Headwind = int(math.Round(speed * cos))
+	out.Crosswind = int(math.Round(speed * sin))
+	if gusts != 0 {
+		out.GustHeadwind = int(math.Round(gusts * cos))
+		out.GustCrosswind = int(math.Round(gusts * sin))
+	}
+	return out
+}
+
+// RunwayWind describes the wind at a runway at a specific time.
+type RunwayWind struct {
+	// Runway is the runway the wind was evaluated against.
+	Runway Runway `json:"runway"`
+
+	// Time is the start of the period in which the wind is forecast.
+	Time time.Time `json:"time"`
+
+	// Wind is the forecast wind.
+	Wind Wind `json:"wind"`
+
+	// Components contains the components of the wind relative to the runway.
+	Components WindComponents `json:"components"`
+}
+
+// MaxCrosswind returns the wind with the strongest crosswind for each of
+// the given runways over the whole validity period of the forecast, in the
+// same order as the runways. Winds in TEMPO, PROB, and in-progress BECMG
+// groups are considered as well as the prevailing wind. If the forecast
+// has no wind for a runway, its Wind and Components are left empty.
+func (fc *Forecast) MaxCrosswind(runways ...Runway) []RunwayWind {
+	out := make([]RunwayWind, len(runways))
+	for i, rwy := range runways {
+		out[i].Runway = rwy
+	}
+
+	check := func(t time.Time, w Wind) {
+		if w.Speed.Unit == "" {
+			return
+		}
+
+		for i := range out {
+			wc := w.Components(out[i].Runway.TrueHeading())
+			if out[i].Wind.Speed.Unit == "" || wc.MaxCrosswind() > out[i].Components.MaxCrosswind() {
+				out[i].Time, out[i].Wind, out[i].Components = t, w, wc
+			}
+		}
+	}
+
+	// The wind can only change at the start or end of a group,
+	// so those are the only times that need to be checked.
+	for _, t := range groupBoundaries(fc.Valid.From, fc.Valid.To, fc) {
+		c := fc.At(t)
+		check(t, c.Wind)
+		for _, alt := range c.Alternates {
+			check(t, alt.Wind)
+		}
+	}
+
+	return out
+}
+
+// normalizeHeading converts a heading to a value between 1 and 360
+func normalizeHeading(h int) int {
+	h %= 360
+	if h <= 0 {
+		h += 360
+	}
+	return h
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,170 @@
+package taf
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"go.elara.ws/taf/airports"
+	"go.elara.ws/taf/units"
+)
+
+func TestParseRunway(t *testing.T) {
+	testCases := []struct {
+		id       string
+		expected Runway
+		ok       bool
+	}{
+		{"24L", Runway{Identifier: "24L", Heading: 240, Magnetic: true}, true},
+		{"RWY09", Runway{Identifier: "09", Heading: 90, Magnetic: true}, true},
+		{"36c", Runway{Identifier: "36C", Heading: 360, Magnetic: true}, true},
+		{"37", Runway{}, false},
+		{"00", Runway{}, false},
+		{"24LR", Runway{}, false},
+		{"L", Runway{}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.id, func(t *testing.T) {
+			rwy, ok := ParseRunway(tc.id)
+			if ok != tc.ok {
+				t.Fatalf("Expected ok to be %t, got %t", tc.ok, ok)
+			}
+
+			if diff := deep.Equal(rwy, tc.expected); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestTrueHeading(t *testing.T) {
+	rwy := Runway{Heading: 360, Magnetic: true, Variation: 5}
+	if h := rwy.TrueHeading(); h != 5 {
+		t.Errorf("Expected 5, got %d", h)
+	}
+
+	rwy = Runway{Heading: 240, Magnetic: true, Variation: -10}
+	if h := rwy.TrueHeading(); h != 230 {
+		t.Errorf("Expected 230, got %d", h)
+	}
+
+	rwy = Runway{Heading: 243, Variation: -10}
+	if h := rwy.TrueHeading(); h != 243 {
+		t.Errorf("Expected 243, got %d", h)
+	}
+}
+
+func TestComponents(t *testing.T) {
+	testCases := []struct {
+		name     string
+		wind     Wind
+		heading  int
+		expected WindComponents
+	}{
+		{
+			name:    "crosswind from the right",
+			wind:    Wind{Direction: Direction{Value: 270}, Speed: units.Velocity{Value: 20, Unit: units.Knots}, Gusts: units.Velocity{Value: 30, Unit: units.Knots}},
+			heading: 240,
+			expected: WindComponents{
+				Headwind:      17,
+				Crosswind:     10,
+				GustHeadwind:  26,
+				GustCrosswind: 15,
+				Unit:          units.Knots,
+			},
+		},
+		{
+			name:     "crosswind from the left",
+			wind:     Wind{Direction: Direction{Value: 180}, Speed: units.Velocity{Value: 10, Unit: units.Knots}},
+			heading:  270,
+			expected: WindComponents{Crosswind: -10, Unit: units.Knots},
+		},
+		{
+			name:     "tailwind",
+			wind:     Wind{Direction: Direction{Value: 60}, Speed: units.Velocity{Value: 10, Unit: units.Knots}},
+			heading:  240,
+			expected: WindComponents{Headwind: -10, Unit: units.Knots},
+		},
+		{
+			name:    "variable",
+			wind:    Wind{Direction: Direction{Variable: true}, Speed: units.Velocity{Value: 5, Unit: units.Knots}, Gusts: units.Velocity{Value: 15, Unit: units.Knots}},
+			heading: 240,
+			expected: WindComponents{
+				Headwind:      -5,
+				Crosswind:     5,
+				GustHeadwind:  -15,
+				GustCrosswind: 15,
+				Unit:          units.Knots,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if diff := deep.Equal(tc.wind.Components(tc.heading), tc.expected); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestMaxCrosswind(t *testing.T) {
+	const data = `TAF EGLL 211100Z 2112/2218 22008KT 9999 FEW040
+  TEMPO 2118/2122 27020G35KT
+  FM220600 VRB03KT CAVOK`
+
+	fc, err := DecodeWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	rwy27, _ := ParseRunway("27")
+	rwy18, _ := ParseRunway("18")
+
+	expected := []RunwayWind{
+		{
+			Runway:     rwy27,
+			Time:       time.Date(2023, time.August, 21, 12, 0, 0, 0, time.UTC),
+			Wind:       fc.Wind,
+			Components: WindComponents{Headwind: 5, Crosswind: -6, Unit: units.Knots},
+		},
+		{
+			Runway: rwy18,
+			Time:   time.Date(2023, time.August, 21, 18, 0, 0, 0, time.UTC),
+			Wind:   fc.Changes[0].Wind,
+			Components: WindComponents{
+				Crosswind:     20,
+				GustCrosswind: 35,
+				Unit:          units.Knots,
+			},
+		},
+	}
+
+	if diff := deep.Equal(fc.MaxCrosswind(rwy27, rwy18), expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func TestAirportRunways(t *testing.T) {
+	rwys := airportRunways([]airports.Runway{
+		{Ident: "09L", Heading: 89.6},
+		{Ident: "27R", Heading: 269.6},
+		{Ident: "H1"},
+		{Ident: "18"},
+	})
+
+	expected := []Runway{
+		{Identifier: "09L", Heading: 90},
+		{Identifier: "27R", Heading: 270},
+		{Identifier: "18", Heading: 180, Magnetic: true},
+	}
+
+	if diff := deep.Equal(rwys, expected); diff != nil {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,334 @@
+package taf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultAviationWeatherURL is the aviationweather.gov endpoint used
+// by AviationWeather when BaseURL isn't set.
+const DefaultAviationWeatherURL = "https://aviationweather.gov/cgi-bin/data/taf.php"
+
+// ErrNotFound is returned by a Source when there's no report
+// for one or more of the requested airports.
+var ErrNotFound = errors.New("taf: report not found")
+
+// Source represents a source of TAF reports.
+type Source interface {
+	// Fetch gets and decodes the current reports for the given
+	// ICAO identifiers. If some of the reports can't be found,
+	// the ones that were found are returned along with an error
+	// wrapping ErrNotFound.
+	Fetch(ctx context.Context, icao ...string) ([]*Forecast, error)
+}
+
+// RawSource is a Source that can also provide the raw text of reports.
+type RawSource interface {
+	Source
+
+	// FetchRaw gets the raw text of the current reports for the given
+	// ICAO identifiers, keyed by identifier. If some of the reports
+	// can't be found, the ones that were found are returned along with
+	// an error wrapping ErrNotFound.
+	FetchRaw(ctx context.Context, icao ...string) (map[string]string, error)
+}
+
+// StatusError is returned by AviationWeather when the server
+// responds with an unexpected status code.
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+// Error returns a string describing the status
+func (se *StatusError) Error() string {
+	return "taf: unexpected status: " + se.Status
+}
+
+// AviationWeather is a Source that gets reports from aviationweather.gov,
+// or from any server that provides the same API.
+type AviationWeather struct {
+	// BaseURL is the URL of the TAF endpoint. If it's empty,
+	// DefaultAviationWeatherURL is used.
+	BaseURL string
+
+	// Client is the HTTP client used to make requests. If it's nil,
+	// http.DefaultClient is used.
+	Client *http.Client
+
+	// Timeout is the maximum duration of each attempt. If it's zero,
+	// there's no timeout other than the one set on the client.
+	Timeout time.Duration
+
+	// Retries is the number of times a request is retried when
+	// it fails because of a network error or a 5xx or 429 status.
+	Retries int
+
+	// RetryDelay is the delay before the first retry. It's doubled
+	// for each retry after that. If it's zero, one second is used.
+	RetryDelay time.Duration
+
+	// Options contains the options used to decode the reports.
+	Options Options
+}
+
+// Fetch gets and decodes the current reports for the given ICAO identifiers.
+func (aw *AviationWeather) Fetch(ctx context.Context, icao ...string) ([]*Forecast, error) {
+	raw, err := aw.FetchRaw(ctx, icao...)
+	return decodeRaw(raw, icao, aw.Options, err)
+}
+
+// FetchRaw gets the raw text of the current reports for the given ICAO identifiers.
+func (aw *AviationWeather) FetchRaw(ctx context.Context, icao ...string) (map[string]string, error) {
+	if len(icao) == 0 {
+		return nil, errors.New("taf: no identifiers provided")
+	}
+
+	ids := upperAll(icao)
+
+	base := aw.BaseURL
+	if base == "" {
+		base = DefaultAviationWeatherURL
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	query.Set("ids", strings.Join(ids, ","))
+	u.RawQuery = query.Encode()
+
+	delay := aw.RetryDelay
+	if delay == 0 {
+		delay = time.Second
+	}
+
+	var data []byte
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		data, retry, err = aw.get(ctx, u.String())
+		if err == nil || !retry || attempt >= aw.Retries {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The server doesn't return an error for non-existent
+	// reports, so an empty body means none of them were found.
+	reports, err := splitReports(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return groupReports(reports, ids)
+}
+
+// get performs a single GET request and returns the response body.
+// If the request failed, retry indicates whether it's worth retrying.
+func (aw *AviationWeather) get(ctx context.Context, u string) (data []byte, retry bool, err error) {
+	if aw.Timeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, aw.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, false, err
+	}
+
+	client := aw.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		// Only retry if the parent context is still valid
+		return nil, !errors.Is(ctx.Err(), context.Canceled), err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusOK:
+	case res.StatusCode == http.StatusNotFound:
+		return nil, false, ErrNotFound
+	case res.StatusCode == http.StatusNoContent:
+		return nil, false, nil
+	default:
+		retry := res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests
+		return nil, retry, &StatusError{StatusCode: res.StatusCode, Status: res.Status}
+	}
+
+	data, err = io.ReadAll(res.Body)
+	if err != nil {
+		return nil, true, err
+	}
+	return data, false, nil
+}
+
+// FileSource is a Source that reads reports from the local filesystem.
+// If Path is a directory, the report for each airport is read from a
+// file in it named after the airport's identifier, with or without a
+// .txt extension. Otherwise, every report in the file at Path is decoded
+// and the ones for the requested airports are returned.
+type FileSource struct {
+	// Path is the path to the file or directory containing the reports.
+	Path string
+
+	// Options contains the options used to decode the reports.
+	Options Options
+}
+
+// Fetch reads and decodes the reports for the given ICAO identifiers.
+// If no identifiers are provided and Path is a file, every report
+// in the file is returned.
+func (fs *FileSource) Fetch(ctx context.Context, icao ...string) ([]*Forecast, error) {
+	if len(icao) == 0 {
+		fl, err := os.Open(fs.Path)
+		if err != nil {
+			return nil, err
+		}
+		defer fl.Close()
+		return DecodeAllWithOptions(fl, fs.Options)
+	}
+
+	raw, err := fs.FetchRaw(ctx, icao...)
+	return decodeRaw(raw, icao, fs.Options, err)
+}
+
+// FetchRaw reads the raw text of the reports for the given ICAO identifiers.
+func (fs *FileSource) FetchRaw(ctx context.Context, icao ...string) (map[string]string, error) {
+	ids := upperAll(icao)
+
+	info, err := os.Stat(fs.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		fl, err := os.Open(fs.Path)
+		if err != nil {
+			return nil, err
+		}
+		defer fl.Close()
+
+		reports, err := splitReports(fl)
+		if err != nil {
+			return nil, err
+		}
+		return groupReports(reports, ids)
+	}
+
+	var errs []error
+	out := map[string]string{}
+	for _, id := range ids {
+		if err := ctx.Err(); err != nil {
+			return out, err
+		}
+
+		data, err := fs.readDir(id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		out[id] = data
+	}
+
+	return out, errors.Join(errs...)
+}
+
+// readDir reads the report for the given identifier
+// from the directory at fs.Path
+func (fs *FileSource) readDir(id string) (string, error) {
+	for _, name := range []string{id + ".txt", id} {
+		data, err := os.ReadFile(filepath.Join(fs.Path, name))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrNotFound, id)
+}
+
+// groupReports returns the first report for each of the given identifiers,
+// along with an error wrapping ErrNotFound if any of them are missing.
+func groupReports(reports []string, ids []string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, report := range reports {
+		id := reportIdentifier(report)
+		if _, ok := out[id]; !ok && slices.Contains(ids, id) {
+			out[id] = report
+		}
+	}
+	return out, checkMissing(out, ids)
+}
+
+// decodeRaw decodes the reports for the given identifiers in order.
+// Any error from fetching the reports is joined with the decoding errors.
+func decodeRaw(raw map[string]string, icao []string, opts Options, err error) ([]*Forecast, error) {
+	errs := []error{err}
+
+	var out []*Forecast
+	for _, id := range upperAll(icao) {
+		report, ok := raw[id]
+		if !ok {
+			continue
+		}
+
+		fc, err := DecodeWithOptions(strings.NewReader(report), opts)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		out = append(out, fc)
+	}
+
+	return out, errors.Join(errs...)
+}
+
+// upperAll returns a copy of ids with every identifier in uppercase
+func upperAll(ids []string) []string {
+	out := make([]string, len(ids))
+	for i := range ids {
+		out[i] = strings.ToUpper(ids[i])
+	}
+	return out
+}
+
+// checkMissing returns an error wrapping ErrNotFound if
+// there's no report for one of the given identifiers
+func checkMissing(raw map[string]string, ids []string) error {
+	var missing []string
+	for _, id := range ids {
+		if _, ok := raw[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrNotFound, strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package taf
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	kjfkData   = "TAF KJFK 212335Z 2200/2306 33012G18KT P6SM FEW060 BKN250"
+	sourceData = kjfkData + "\nTAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040"
+)
+
+func TestAviationWeather(t *testing.T) {
+	var attempts int
+	srv := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		attempts++
+		switch req.URL.Query().Get("ids") {
+		case "KJFK,EGLL":
+			// Fail the first attempt to check that it's retried
+			if attempts == 1 {
+				http.Error(res, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			res.Write([]byte(sourceData))
+		case "KJFK,LFBD":
+			res.Write([]byte(kjfkData))
+		case "EGLL":
+			http.Error(res, "bad request", http.StatusBadRequest)
+		case "XXXX":
+			// aviationweather.gov returns an empty body for unknown airports
+		default:
+			http.NotFound(res, req)
+		}
+	}))
+	defer srv.Close()
+
+	aw := &AviationWeather{
+		BaseURL:    srv.URL,
+		Client:     srv.Client(),
+		Retries:    1,
+		RetryDelay: time.Millisecond,
+		Options:    Options{Year: 2023, Month: time.August},
+	}
+
+	fcs, err := aw.Fetch(context.Background(), "kjfk", "egll")
+	if err != nil {
+		t.Fatalf("Error fetching reports: %s", err)
+	}
+
+	if len(fcs) != 2 || fcs[0].Identifier != "KJFK" || fcs[1].Identifier != "EGLL" {
+		t.Errorf("Expected KJFK and EGLL reports, got %d reports", len(fcs))
+	}
+
+	if attempts != 2 {
+		t.Errorf("Expected 2 attempts, got %d", attempts)
+	}
+
+	fcs, err = aw.Fetch(context.Background(), "KJFK", "LFBD")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for missing report, got %v", err)
+	}
+
+	if len(fcs) != 1 {
+		t.Errorf("Expected the reports that were found to be returned, got %d reports", len(fcs))
+	}
+
+	if _, err = aw.Fetch(context.Background(), "XXXX"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for empty body, got %v", err)
+	}
+
+	if _, err = aw.Fetch(context.Background(), "YYYY"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for 404, got %v", err)
+	}
+
+	var se *StatusError
+	if _, err = aw.Fetch(context.Background(), "EGLL"); !errors.As(err, &se) || se.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected StatusError with status 400, got %v", err)
+	}
+}
+
+func TestFileSource(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "all.txt")
+	if err := os.WriteFile(path, []byte(sourceData), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	opts := Options{Year: 2023, Month: time.August}
+
+	fcs, err := (&FileSource{Path: path, Options: opts}).Fetch(context.Background(), "EGLL")
+	if err != nil {
+		t.Fatalf("Error fetching reports: %s", err)
+	}
+
+	if len(fcs) != 1 || fcs[0].Identifier != "EGLL" {
+		t.Errorf("Expected only the EGLL report, got %d reports", len(fcs))
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "KJFK.txt"), []byte(kjfkData), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fcs, err = (&FileSource{Path: dir, Options: opts}).Fetch(context.Background(), "KJFK", "LFBD")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for missing report, got %v", err)
+	}
+
+	if len(fcs) != 1 || fcs[0].Identifier != "KJFK" {
+		t.Errorf("Expected the KJFK report, got %d reports", len(fcs))
+	}
+}
@@ -0,0 +1,55 @@
+package taf
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// spanRecorder records the parts of the original text
+// that the fields of each group were decoded from.
+type spanRecorder struct {
+	src     string
+	enabled bool
+}
+
+// add records the span of the text from pos to end as the source of the
+// named field of a group. If the field is a list, the span is recorded
+// for its last element.
+func (sr spanRecorder) add(rv reflect.Value, name string, pos lexer.Position, end int) {
+	if !sr.enabled {
+		return
+	}
+
+	sf, _ := rv.Type().FieldByName(name)
+	field, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if fv := rv.FieldByName(name); fv.Kind() == reflect.Slice {
+		field += "[" + strconv.Itoa(fv.Len()-1) + "]"
+	}
+
+	appendField(rv, "Spans", Span{
+		Field:  field,
+		Offset: pos.Offset,
+		Line:   pos.Line,
+		Column: pos.Column,
+		Token:  sr.src[pos.Offset:end],
+	})
+}
+
+// tokensEnd returns the offset right after the last of the given tokens,
+// not including any whitespace after it. Only tokens that start before
+// limit are considered.
+func tokensEnd(tokens []lexer.Token, limit int) int {
+	end := 0
+	for _, t := range tokens {
+		if t.Pos.Offset >= limit {
+			break
+		}
+		if strings.TrimSpace(t.Value) != "" {
+			end = t.Pos.Offset + len(t.Value)
+		}
+	}
+	return end
+}
@@ -0,0 +1,71 @@
+package taf
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func TestSpans(t *testing.T) {
+	const data = `TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040
+  BECMG 2201/2204 BKN007
+  PROB30
+  TEMPO 2202/2206 8000 BKN004 RMK NXT FCST BY 00Z`
+
+	fc, err := DecodeWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+		Spans: true,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	expected := []Span{
+		{Field: "identifier", Offset: 4, Line: 1, Column: 5, Token: "EGLL"},
+		{Field: "publish_time", Offset: 9, Line: 1, Column: 10, Token: "211658Z"},
+		{Field: "valid", Offset: 17, Line: 1, Column: 18, Token: "2118/2224"},
+		{Field: "wind", Offset: 27, Line: 1, Column: 28, Token: "22008KT"},
+		{Field: "visibility", Offset: 35, Line: 1, Column: 36, Token: "9999"},
+		{Field: "sky_condition[0]", Offset: 40, Line: 1, Column: 41, Token: "FEW040"},
+		{Field: "remark", Offset: 111, Line: 4, Column: 31, Token: "RMK NXT FCST BY 00Z"},
+	}
+	if diff := deep.Equal(fc.Spans, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	expected = []Span{
+		{Field: "probability", Offset: 74, Line: 3, Column: 3, Token: "PROB30"},
+		{Field: "type", Offset: 83, Line: 4, Column: 3, Token: "TEMPO 2202/2206"},
+		{Field: "visibility", Offset: 99, Line: 4, Column: 19, Token: "8000"},
+		{Field: "sky_condition[0]", Offset: 104, Line: 4, Column: 24, Token: "BKN004"},
+	}
+	if diff := deep.Equal(fc.Changes[1].Spans, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	if raw := fc.Changes[1].Raw; raw != "PROB30\n  TEMPO 2202/2206 8000 BKN004" {
+		t.Errorf("Unexpected raw change text: %q", raw)
+	}
+
+	if fc.Raw != data {
+		t.Errorf("Expected raw text to match the report, got %q", fc.Raw)
+	}
+}
+
+func TestSpansDisabled(t *testing.T) {
+	fc, err := DecodeString("TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040 BECMG 2201/2204 BKN007")
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	if fc.Spans != nil || fc.Changes[0].Spans != nil {
+		t.Error("Expected spans not to be recorded by default")
+	}
+
+	if fc.Changes[0].Raw != "BECMG 2201/2204 BKN007" {
+		t.Errorf("Unexpected raw change text: %q", fc.Changes[0].Raw)
+	}
+}
@@ -10,7 +10,7 @@ import (
 	"strings"
 	"time"
 
-	"github.com/alecthomas/participle/v2"
+	"github.com/alecthomas/participle/v2/lexer"
 	"go.elara.ws/taf/airports"
 	"go.elara.ws/taf/internal/parser"
 	"go.elara.ws/taf/units"
@@ -51,6 +51,24 @@ type Options struct {
 	// be converted to the given unit
 	SpeedUnit units.Speed
 
+	// If this is set, the temperatures in TX and TN groups
+	// will be converted to the given unit
+	TemperatureUnit units.Temperature
+
+	// If this is set, all altimeter settings in the forecast
+	// will be converted to the given unit
+	PressureUnit units.Pressure
+
+	// If this is set, all heights in the forecast, such as cloud
+	// bases, wind shear heights, and icing and turbulence layers,
+	// will be converted to the given unit. Use units.ParseHeight to parse it.
+	HeightUnit units.HeightUnit
+
+	// Rounding specifies how converted values that are stored
+	// as whole numbers, such as wind speeds, are rounded. By
+	// default, they're rounded to the nearest whole number.
+	Rounding units.Rounding
+
 	// The Year field is used to calculate the full date that this
 	// report was published. If it's unset, the current year will be used.
 	Year int
@@ -58,275 +76,267 @@ type Options struct {
 	// The Month field is used to calculate the full date that this
 	// report was published. If it's unset, the current month will be used.
 	Month time.Month
+
+	// The Reference field is used to resolve the day and hour groups in
+	// the report. The publish time is resolved to the closest matching
+	// date to Reference, and every other time is resolved to the closest
+	// matching date to the publish time, so times crossing the end of a
+	// month or year are handled correctly. If it's set, Year and Month
+	// are ignored. If neither Reference, Year, nor Month are set, the
+	// current time is used.
+	Reference time.Time
+
+	// If this is set, groups that can't be decoded will be skipped and
+	// recorded in the Warnings and Unparsed fields of the result rather
+	// than causing an error.
+	Lenient bool
+
+	// If this is set, the part of the original text that each group was
+	// decoded from is recorded in the Spans field of the forecast, change,
+	// or probability group it belongs to.
+	Spans bool
 }
 
-// DecodeWithOptions decodes the data in a reader and returns a Forecast
-func DecodeWithOptions(r io.Reader, opts Options) (*Forecast, error) {
-	filename := "unknown"
+// readerName returns the name that should be used
+// for a reader in error messages.
+func readerName(r io.Reader) string {
 	switch r := r.(type) {
 	case *os.File:
-		filename = r.Name()
+		return r.Name()
 	case fs.File:
 		fi, err := r.Stat()
 		if err == nil {
-			filename = fi.Name()
+			return fi.Name()
 		}
 	case *strings.Reader:
-		filename = "string"
-	}
-
-	if opts.Year == 0 {
-		opts.Year = time.Now().Year()
+		return "string"
 	}
+	return "unknown"
+}
 
-	if opts.Month == 0 {
-		opts.Month = time.Now().Month()
+// DecodeWithOptions decodes the data in a reader and returns a Forecast
+func DecodeWithOptions(r io.Reader, opts Options) (*Forecast, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
 	}
 
-	ast, err := parser.Parser.Parse(filename, r)
+	src := string(data)
+	ps := &problems{}
+	ast, err := parseWithRecovery(parser.Parser, readerName(r), src, ps)
 	if err != nil {
 		return nil, err
 	}
 
-	setProb := 0
-	fc := &Forecast{}
-	out := reflect.ValueOf(fc).Elem()
+	var (
+		setProb int
+		probPos lexer.Position
+		probEnd int
+
+		// section points to the raw text of the current change or
+		// probability group, which starts at sectionStart
+		section      *string
+		sectionStart int
+	)
+
+	ref := opts.reference()
+	spans := spanRecorder{src: src, enabled: opts.Spans}
+	fc := &Forecast{Header: ast.Header, Raw: strings.TrimSpace(src)}
+	root := reflect.ValueOf(fc).Elem()
+	out := root
 
 	if ast.Type != nil {
 		fc.ReportType = convertReportType(*ast.Type)
 	}
 
 	for _, item := range ast.Items {
+		end := tokensEnd(item.Tokens, len(src))
+
+		// Change and probability groups extend until the next one, except
+		// for remarks, which apply to the whole forecast.
+		if section != nil && item.Change == nil && item.Probability == nil && item.Remark == nil {
+			*section = src[sectionStart:end]
+		}
+
 		switch {
 		case item.ID != nil:
+			// The first identifier is the airport. Any other
+			// unknown group is a problem.
+			if fc.Identifier != "" {
+				ps.add(problemf(item.Pos, KindUnknown, "unrecognized group"))
+				continue
+			}
+
 			fc.Identifier = *item.ID
 			if a, ok := airports.Airports[fc.Identifier]; ok {
 				fc.Airport = a
 			}
+			spans.add(root, "Identifier", item.Pos, end)
 		case item.Time != nil:
-			t, err := parseTime(*item.Time, opts.Month, opts.Year)
+			t, err := parseIssueTime(*item.Time, opts)
 			if err != nil {
-				return nil, participle.Errorf(item.Pos, "time: %s", err)
+				ps.add(problemf(item.Pos, KindTime, "%s", err))
+				continue
 			}
 			setField(out, "PublishTime", t)
+			// Every other time in the report is relative to the publish time
+			ref = t
+
+			// The Time item comes with a Valid as well because of the way
+			// it's parsed into the AST, unless the report is missing (NIL).
+			if item.Valid == nil {
+				spans.add(out, "PublishTime", item.Pos, end)
+				continue
+			}
+			spans.add(out, "PublishTime", item.Pos, tokensEnd(item.Tokens, item.Valid.Pos.Offset))
 
-			// The Time item always comes with a Valid as well because
-			// of the way it's parsed into the AST
-			vp, err := parseValid(item.Valid, opts.Month, opts.Year)
+			vp, err := parseValid(item.Valid, ref)
 			if err != nil {
-				return nil, participle.Errorf(item.Pos, "time: %s", err)
+				ps.add(problemf(item.Pos, KindTime, "%s", err))
+				continue
 			}
 			setField(out, "Valid", vp)
+			spans.add(out, "Valid", item.Valid.Pos, end)
 		case item.Weather != nil:
-			appendField(out, "Weather", Weather{
-				Modifier:      convertModifier(item.Weather.Modifier),
-				Descriptor:    convertDescriptor(item.Weather.Descriptor),
-				Precipitation: convertPrecipitation(item.Weather.Precipitation),
-				Obscuration:   convertObscuration(item.Weather.Obscuration),
-				Phenomenon:    convertPhenomenon(item.Weather.Other),
-			})
+			appendField(out, "Weather", parseWeather(item.Weather))
+			spans.add(out, "Weather", item.Pos, end)
 		case item.Vicinity != nil:
-			appendField(out, "Weather", Weather{
-				Vicinity:      true,
-				Descriptor:    convertDescriptor(item.Vicinity.Descriptor),
-				Precipitation: convertPrecipitation(item.Vicinity.Precipitation),
-			})
+			appendField(out, "Weather", parseVicinity(item.Vicinity))
+			spans.add(out, "Weather", item.Pos, end)
 		case item.SkyCondition != nil:
-			var altitude int
-			if item.SkyCondition.Altitude != "" {
-				altitude, err = strconv.Atoi(item.SkyCondition.Altitude)
-				if err != nil {
-					return nil, participle.Errorf(item.SkyCondition.Pos, "sky: %s", err)
-				}
+			sc, err := parseSkyCondition(item.SkyCondition, opts)
+			if err != nil {
+				ps.add(err)
+				continue
 			}
-
-			appendField(out, "SkyCondition", SkyCondition{
-				Altitude:  altitude * 100, // Scale factor for altitude is 100
-				Type:      convertSkyConditionType(item.SkyCondition.Type),
-				CloudType: convertCloudType(item.SkyCondition.CloudType),
-			})
+			appendField(out, "SkyCondition", sc)
+			spans.add(out, "SkyCondition", item.Pos, end)
 		case item.Temperature != nil:
-			vt, err := parseValidTime(item.Temperature.Time, opts.Month, opts.Year)
+			temp, err := parseTemperature(item.Temperature, ref, opts)
 			if err != nil {
-				return nil, participle.Errorf(item.Temperature.Pos, "temp: %s", err)
+				ps.add(problemf(item.Temperature.Pos, KindTemperature, "%s", err))
+				continue
 			}
-
-			val, err := strconv.Atoi(item.Temperature.Value)
+			appendField(out, "Temperature", temp)
+			spans.add(out, "Temperature", item.Pos, end)
+		case item.Altimeter != nil:
+			alt, err := parseQNH(item.Altimeter.Value, item.Altimeter.Inches)
 			if err != nil {
-				return nil, participle.Errorf(item.Temperature.Pos, "temp: %s", err)
-			}
-
-			appendField(out, "Temperature", Temperature{
-				Type:  convertTemperatureType(item.Temperature.Type),
-				Time:  vt,
-				Value: val,
-			})
-		case item.Visibility != nil:
-			// This value may have a space at the end if there's no unit
-			item.Visibility.Value = strings.TrimSpace(item.Visibility.Value)
-
-			// Create a new rational number
-			ratNum := new(big.Rat)
-			// If there's a space, this is a mixed number, split it at the space
-			if before, after, ok := strings.Cut(item.Visibility.Value, " "); ok {
-				// Set the rational number to the fraction of the mixed number
-				ratNum, ok = ratNum.SetString(after)
-				if !ok {
-					return nil, participle.Errorf(item.Visibility.Pos, "visibility: invalid fraction %q", after)
-				}
-
-				// Create a new rational number and set it to the whole part of
-				// the mixed number
-				add, ok := new(big.Rat).SetString(before)
-				if !ok {
-					return nil, participle.Errorf(item.Visibility.Pos, "visibility: invalid whole number %q", before)
-				}
-
-				// Add the whole part to the fractional part
-				ratNum = ratNum.Add(ratNum, add)
-			} else {
-				// There's no space, so this is just a fraction or a whole number.
-				// Just set the rational number to the whole string.
-				ratNum, ok = ratNum.SetString(before)
-				if !ok {
-					return nil, participle.Errorf(item.Visibility.Pos, "visibility: invalid fraction %q", after)
-				}
+				ps.add(problemf(item.Altimeter.Pos, KindAltimeter, "%s", err))
+				continue
 			}
-
-			// If there's no unit, set the unit to meters
-			if item.Visibility.Unit == "" {
-				item.Visibility.Unit = "M"
+			setField(out, "Altimeter", opts.unitSystem().convertAltimeter(alt))
+			spans.add(out, "Altimeter", item.Pos, end)
+		case item.Icing != nil:
+			ic, err := parseIcing(*item.Icing, opts)
+			if err != nil {
+				ps.add(problemf(item.Pos, KindIcing, "%s", err))
+				continue
 			}
-
-			unit, ok := units.ParseDistance(item.Visibility.Unit)
-			if !ok {
-				return nil, participle.Errorf(item.Visibility.Pos, "visibility: invalid unit %q", item.Visibility.Unit)
+			appendField(out, "Icing", ic)
+			spans.add(out, "Icing", item.Pos, end)
+		case item.Turbulence != nil:
+			tb, err := parseTurbulence(*item.Turbulence, opts)
+			if err != nil {
+				ps.add(problemf(item.Pos, KindTurbulence, "%s", err))
+				continue
 			}
-
-			val, _ := ratNum.Float64()
-
-			if opts.DistanceUnit != "" {
-				val = unit.Convert(opts.DistanceUnit, val)
-				unit = opts.DistanceUnit
+			appendField(out, "Turbulence", tb)
+			spans.add(out, "Turbulence", item.Pos, end)
+		case item.Visibility != nil:
+			vis, err := parseVisibility(item.Visibility, opts)
+			if err != nil {
+				ps.add(err)
+				continue
 			}
-
-			setField(out, "Visibility", Visibility{
-				Plus:  item.Visibility.Plus,
-				Value: val,
-				Unit:  unit,
-			})
+			setField(out, "Visibility", vis)
+			spans.add(out, "Visibility", item.Pos, end)
 		case item.WindSpeed != nil:
-			var direction int
-			// If the wind speed is variable, there's no direction to worry about
-			if !item.WindSpeed.Variable {
-				// The length of the value must be at least 5 (3 characters for direction and 2 for speed)
-				if len(item.WindSpeed.Value) < 5 {
-					return nil, participle.Errorf(item.WindSpeed.Pos, "wind: invalid length (%d)", len(item.WindSpeed.Value))
-				}
-
-				// First three characters are the direction
-				direction, err = strconv.Atoi(item.WindSpeed.Value[:3])
-				if err != nil {
-					return nil, participle.Errorf(item.WindSpeed.Pos, "wind: %s", err)
-				}
-
-				// Set the value to the last two characters so it can be processed
-				// as just a speed.
-				item.WindSpeed.Value = item.WindSpeed.Value[3:]
-
-				// The direction is in degrees so it may not go above 360 or below 0
-				if direction > 360 || direction < 0 {
-					return nil, participle.Errorf(item.WindSpeed.Pos, "wind: invalid direction (%d)", direction)
-				}
-			}
-
-			// If there was a direction, it was removed above, so now we can just
-			// get the speed by parsing the string
-			speed, err := strconv.Atoi(item.WindSpeed.Value)
+			wind, err := parseWind(item.WindSpeed, opts)
 			if err != nil {
-				return nil, participle.Errorf(item.WindSpeed.Pos, "wind: %s", err)
-			}
-
-			var gusts int
-			if item.WindSpeed.Gusts != "" {
-				gusts, err = strconv.Atoi(item.WindSpeed.Gusts)
-				if err != nil {
-					return nil, participle.Errorf(item.WindSpeed.Pos, "wind: %s", err)
-				}
-			}
-
-			var windshear int
-			if item.WindSpeed.WindShear != "" {
-				windshear, err = strconv.Atoi(item.WindSpeed.WindShear)
-				if err != nil {
-					return nil, participle.Errorf(item.WindSpeed.Pos, "wind: %s", err)
-				}
-			}
-
-			unit, ok := units.ParseSpeed(item.WindSpeed.Unit)
-			if !ok {
-				return nil, participle.Errorf(item.WindSpeed.Pos, "wind: invalid unit %q", item.Visibility.Unit)
+				ps.add(err)
+				continue
 			}
 
-			if opts.SpeedUnit != "" {
-				speed = unit.Convert(opts.SpeedUnit, speed)
-				if gusts != 0 {
-					gusts = unit.Convert(opts.SpeedUnit, gusts)
-				}
-				unit = opts.SpeedUnit
+			// Wind shear groups are separate from the surface wind
+			if wind.WindShear.Value != 0 {
+				setField(out, "WindShear", wind)
+				spans.add(out, "WindShear", item.Pos, end)
+			} else {
+				setField(out, "Wind", wind)
+				spans.add(out, "Wind", item.Pos, end)
 			}
-
-			setField(out, "Wind", Wind{
-				Gusts:     gusts,
-				Speed:     speed,
-				WindShear: windshear * 100, // Scale factor for altitude is 100
-				Direction: Direction{
-					Variable: item.WindSpeed.Variable,
-					Value:    direction,
-				},
-				Unit: unit,
-			})
 		case item.Flag != nil:
+			// Flags belong to the current group, unless they
+			// apply to the whole forecast
+			target := out
 			switch {
 			case item.Flag.CAVOK:
 				appendField(out, "Flags", CeilingAndVisibilityOK)
+			case item.Flag.NSW:
+				appendField(out, "Flags", NoSignificantWeather)
+			case item.Flag.NSC:
+				appendField(out, "Flags", NoSignificantCloud)
+			case item.Flag.NCD:
+				appendField(out, "Flags", NoCloudDetected)
+			case item.Flag.Cancelled:
+				// Cancellation applies to the whole forecast
+				fc.Flags = append(fc.Flags, Cancelled)
+				target = root
+			case item.Flag.Nil:
+				fc.Flags = append(fc.Flags, Missing)
+				target = root
 			}
+			spans.add(target, "Flags", item.Pos, end)
 		case item.Change != nil:
 			ch := &Change{
 				Type: convertChangeType(item.Change.Type),
 			}
 
+			// The raw text of the change starts at its PROB group, if it has one
+			section, sectionStart = &ch.Raw, item.Pos.Offset
+			if setProb != 0 {
+				sectionStart = probPos.Offset
+			}
+			ch.Raw = src[sectionStart:end]
+
 			// if setProb is set, add the probability within it to the change,
 			// then reset the variable.
 			if setProb != 0 {
 				ch.Probability = setProb
+				ch.probOnOwnLine = probPos.Line != item.Pos.Line
 				setProb = 0
+				spans.add(reflect.ValueOf(ch).Elem(), "Probability", probPos, probEnd)
 			}
 
 			// FM changes don't have a valid pair, they only come with a single time string
+			var err error
 			if ch.Type == From {
-				t, err := parseTime(item.Change.Time, opts.Month, opts.Year)
-				if err != nil {
-					return nil, participle.Errorf(item.Change.Pos, "changes: %s", err)
-				}
-				ch.Valid = ValidPair{From: t}
+				ch.Valid.From, err = parseTime(item.Change.Time, ref)
 			} else {
-				vp, err := parseValid(item.Change.Valid, opts.Month, opts.Year)
-				if err != nil {
-					return nil, participle.Errorf(item.Change.Pos, "changes: %s", err)
-				}
-				ch.Valid = vp
+				ch.Valid, err = parseValid(item.Change.Valid, ref)
 			}
 
+			if err != nil {
+				ps.add(problemf(item.Change.Pos, KindChange, "%s", err))
+				// Skip the change along with its groups, rather than
+				// adding them to the previous change or the forecast.
+				out, section = reflect.ValueOf(ch).Elem(), nil
+				continue
+			}
+
+			ch.order = len(fc.Changes) + len(fc.Probabilities) + 1
 			fc.Changes = append(fc.Changes, ch)
 
 			// Set out to the change value so that future mutations
 			// happen to the change rather than the root forecast.
 			out = reflect.ValueOf(ch).Elem()
+			spans.add(out, "Type", item.Pos, end)
 		case item.Probability != nil:
 			prob, err := strconv.Atoi(item.Probability.Value)
 			if err != nil {
-				return nil, participle.Errorf(item.Probability.Pos, "prob: %s", err)
+				ps.add(problemf(item.Probability.Pos, KindProbability, "%s", err))
+				continue
 			}
 
 			// If the time is empty, this probability belongs to the
@@ -334,28 +344,247 @@ func DecodeWithOptions(r io.Reader, opts Options) (*Forecast, error) {
 			if item.Probability.Valid.Start == "" {
 				// Set the setProb variable. This will let the decoder know to add it to the next change.
 				setProb = prob
+				probPos, probEnd = item.Pos, end
 			} else {
-				pr := &Probability{Value: prob}
+				pr := &Probability{Value: prob, Raw: src[item.Pos.Offset:end]}
+				section, sectionStart = &pr.Raw, item.Pos.Offset
 
-				pr.Valid, err = parseValid(&item.Probability.Valid, opts.Month, opts.Year)
+				pr.Valid, err = parseValid(&item.Probability.Valid, ref)
 				if err != nil {
-					return nil, participle.Errorf(item.Probability.Pos, "prob: %s", err)
+					ps.add(problemf(item.Probability.Pos, KindProbability, "%s", err))
+					// Skip the probability group along with its groups, like changes
+					out, section = reflect.ValueOf(pr).Elem(), nil
+					continue
 				}
 
+				pr.order = len(fc.Changes) + len(fc.Probabilities) + 1
 				fc.Probabilities = append(fc.Probabilities, pr)
 
 				// Set out to the probability value so that future mutations
 				// happen to the probability rather than the root forecast.
 				out = reflect.ValueOf(pr).Elem()
+				spans.add(out, "Value", item.Pos, end)
 			}
 		case item.Remark != nil:
 			fc.Remark = strings.TrimSpace(strings.TrimPrefix(*item.Remark, "RMK"))
+			spans.add(root, "Remark", item.Pos, end)
 		}
 	}
 
+	if err := ps.result(opts.Lenient); err != nil {
+		return nil, err
+	}
+
+	fc.Warnings = ps.list
+	fc.Unparsed = ps.unparsed()
 	return fc, nil
 }
 
+// parseWeather converts a weather AST node into a Weather value
+func parseWeather(w *parser.Weather) Weather {
+	return Weather{
+		Modifier:      convertModifier(w.Modifier),
+		Descriptor:    convertDescriptor(w.Descriptor),
+		Precipitation: convertPrecipitation(w.Precipitation),
+		Obscuration:   convertObscuration(w.Obscuration),
+		Phenomenon:    convertPhenomenon(w.Other),
+	}
+}
+
+// parseVicinity converts a vicinity AST node into a Weather value
+func parseVicinity(v *parser.Vicinity) Weather {
+	return Weather{
+		Vicinity:      true,
+		Descriptor:    convertDescriptor(v.Descriptor),
+		Precipitation: convertPrecipitation(v.Precipitation),
+	}
+}
+
+// parseSkyCondition converts a sky condition AST node into a SkyCondition
+// value, converting its altitude to opts.HeightUnit if it's set.
+func parseSkyCondition(sc *parser.SkyCondition, opts Options) (SkyCondition, error) {
+	out := SkyCondition{
+		Type:      convertSkyConditionType(sc.Type),
+		CloudType: convertCloudType(sc.CloudType),
+	}
+
+	// Groups such as SKC don't have an altitude, so it's left unset
+	if sc.Altitude != "" {
+		altitude, err := strconv.Atoi(sc.Altitude)
+		if err != nil {
+			return SkyCondition{}, problemf(sc.Pos, KindSky, "%s", err)
+		}
+		out.Altitude = opts.unitSystem().convertHeight(units.HeightFeet(float64(altitude * 100))) // Scale factor for altitude is 100
+	}
+
+	return out, nil
+}
+
+// parseVisibility converts a visibility AST node into a Visibility value,
+// converting it to opts.DistanceUnit if it's set.
+func parseVisibility(v *parser.Visibility, opts Options) (Visibility, error) {
+	// This value may have a space at the end if there's no unit
+	v.Value = strings.TrimSpace(v.Value)
+
+	// Create a new rational number
+	ratNum := new(big.Rat)
+	// If there's a space, this is a mixed number, split it at the space
+	if before, after, ok := strings.Cut(v.Value, " "); ok {
+		// Set the rational number to the fraction of the mixed number
+		ratNum, ok = ratNum.SetString(after)
+		if !ok {
+			return Visibility{}, problemf(v.Pos, KindVisibility, "invalid fraction %q", after)
+		}
+
+		// Create a new rational number and set it to the whole part of
+		// the mixed number
+		add, ok := new(big.Rat).SetString(before)
+		if !ok {
+			return Visibility{}, problemf(v.Pos, KindVisibility, "invalid whole number %q", before)
+		}
+
+		// Add the whole part to the fractional part
+		ratNum = ratNum.Add(ratNum, add)
+	} else {
+		// There's no space, so this is just a fraction or a whole number.
+		// Just set the rational number to the whole string.
+		ratNum, ok = ratNum.SetString(before)
+		if !ok {
+			return Visibility{}, problemf(v.Pos, KindVisibility, "invalid fraction %q", before)
+		}
+	}
+
+	// If there's no unit, set the unit to meters
+	if v.Unit == "" {
+		v.Unit = "M"
+	}
+
+	unit, ok := units.ParseDistance(v.Unit)
+	if !ok {
+		return Visibility{}, problemf(v.Pos, KindVisibility, "invalid unit %q", v.Unit)
+	}
+
+	val, _ := ratNum.Float64()
+
+	return opts.unitSystem().convertVisibility(Visibility{
+		Plus:   v.Plus,
+		Minus:  v.Minus,
+		Length: units.Length{Value: val, Unit: unit},
+	}), nil
+}
+
+// parseWind converts a wind AST node into a Wind value, converting its
+// speeds to opts.SpeedUnit and its wind shear height to opts.HeightUnit
+// if they're set.
+func parseWind(ws *parser.WindSpeed, opts Options) (Wind, error) {
+	var (
+		direction int
+		err       error
+	)
+	// If the wind speed is variable, there's no direction to worry about
+	if !ws.Variable {
+		// The length of the value must be at least 5 (3 characters for direction and 2 for speed)
+		if len(ws.Value) < 5 {
+			return Wind{}, problemf(ws.Pos, KindWind, "invalid length (%d)", len(ws.Value))
+		}
+
+		// First three characters are the direction
+		direction, err = strconv.Atoi(ws.Value[:3])
+		if err != nil {
+			return Wind{}, problemf(ws.Pos, KindWind, "%s", err)
+		}
+
+		// Set the value to the last two characters so it can be processed
+		// as just a speed.
+		ws.Value = ws.Value[3:]
+
+		// The direction is in degrees so it may not go above 360 or below 0
+		if direction > 360 || direction < 0 {
+			return Wind{}, problemf(ws.Pos, KindWind, "invalid direction (%d)", direction)
+		}
+	}
+
+	// If there was a direction, it was removed above, so now we can just
+	// get the speed by parsing the string
+	speed, err := strconv.Atoi(ws.Value)
+	if err != nil {
+		return Wind{}, problemf(ws.Pos, KindWind, "%s", err)
+	}
+
+	var gusts int
+	if ws.Gusts != "" {
+		gusts, err = strconv.Atoi(ws.Gusts)
+		if err != nil {
+			return Wind{}, problemf(ws.Pos, KindWind, "%s", err)
+		}
+	}
+
+	var windshear int
+	if ws.WindShear != "" {
+		windshear, err = strconv.Atoi(ws.WindShear)
+		if err != nil {
+			return Wind{}, problemf(ws.Pos, KindWind, "%s", err)
+		}
+	}
+
+	unit, ok := units.ParseSpeed(ws.Unit)
+	if !ok {
+		return Wind{}, problemf(ws.Pos, KindWind, "invalid unit %q", ws.Unit)
+	}
+
+	out := Wind{
+		Speed: units.Velocity{Value: float64(speed), Unit: unit},
+		Direction: Direction{
+			Variable: ws.Variable,
+			Value:    direction,
+		},
+	}
+	if gusts != 0 {
+		out.Gusts = units.Velocity{Value: float64(gusts), Unit: unit}
+	}
+	if windshear != 0 {
+		out.WindShear = units.HeightFeet(float64(windshear * 100)) // Scale factor for altitude is 100
+	}
+	return opts.unitSystem().convertWind(out), nil
+}
+
+// parseTemperature parses a temperature group, such as TX25/2118Z.
+// US military TAFs may also give the temperature as T25/18Z, and
+// negative temperatures may be written as TNM02/2212Z. The temperature
+// is converted to opts.TemperatureUnit if it's set.
+func parseTemperature(t *parser.Temperature, ref time.Time, opts Options) (Temperature, error) {
+	var (
+		tt  time.Time
+		err error
+	)
+	if len(t.Time) == 2 {
+		tt, err = parseHourTime(t.Time, ref)
+	} else {
+		tt, err = parseValidTime(t.Time, ref)
+	}
+	if err != nil {
+		return Temperature{}, err
+	}
+
+	val, err := strconv.Atoi(t.Value)
+	if err != nil {
+		return Temperature{}, err
+	}
+
+	// The M prefix of the value is lexed as part of the type
+	typ, negative := strings.CutSuffix(t.Type, "M")
+	if negative {
+		val = -val
+	}
+
+	return opts.unitSystem().convertTemperature(Temperature{
+		Type:  convertTemperatureType(typ),
+		Time:  tt,
+		Value: val,
+		Unit:  units.Celsius,
+	}), nil
+}
+
 // setField sets a field of a struct to a value.
 //
 // This is used to allow mutations to happen on either
@@ -1,6 +1,7 @@
 package taf
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 	"time"
@@ -19,6 +20,7 @@ func TestKLAX(t *testing.T) {
   FM222000 26012KT P6SM SCT030`
 
 	expected := &Forecast{
+		Raw:        data,
 		Identifier: "KLAX",
 		Airport: airports.Airport{
 			ICAO:      "KLAX",
@@ -39,149 +41,142 @@ func TestKLAX(t *testing.T) {
 			Duration: time.Duration(100800000000000),
 		},
 		Visibility: Visibility{
-			Plus:  true,
-			Value: 6,
-			Unit:  units.Miles,
+			Plus:   true,
+			Length: units.Length{Value: 6, Unit: units.Miles},
 		},
 		Wind: Wind{
 			Direction: Direction{
 				Value: 260,
 			},
-			Speed: 12,
-			Unit:  units.Knots,
+			Speed: units.Velocity{Value: 12, Unit: units.Knots},
 		},
 		SkyCondition: []SkyCondition{
 			{
 				Type:     Few,
-				Altitude: 3500,
+				Altitude: units.HeightFeet(3500),
 			},
 			{
 				Type:     Scattered,
-				Altitude: 5000,
+				Altitude: units.HeightFeet(5000),
 			},
 			{
 				Type:     Scattered,
-				Altitude: 6000,
+				Altitude: units.HeightFeet(6000),
 			},
 		},
 		Changes: []*Change{
 			{
 				Type: From,
+				Raw:  "FM212200 25010KT P6SM SCT040",
 				Valid: ValidPair{
 					From: time.Date(2023, time.August, 21, 22, 0, 0, 0, time.UTC),
 				},
 				Visibility: Visibility{
-					Plus:  true,
-					Value: 6,
-					Unit:  units.Miles,
+					Plus:   true,
+					Length: units.Length{Value: 6, Unit: units.Miles},
 				},
 				Wind: Wind{
 					Direction: Direction{
 						Value: 250,
 					},
-					Speed: 10,
-					Unit:  units.Knots,
+					Speed: units.Velocity{Value: 10, Unit: units.Knots},
 				},
 				SkyCondition: []SkyCondition{
 					{
 						Type:     Scattered,
-						Altitude: 4000,
+						Altitude: units.HeightFeet(4000),
 					},
 				},
 			},
 			{
 				Type: From,
+				Raw:  "FM220300 VRB03KT P6SM BKN025",
 				Valid: ValidPair{
 					From: time.Date(2023, time.August, 22, 3, 0, 0, 0, time.UTC),
 				},
 				Visibility: Visibility{
-					Plus:  true,
-					Value: 6,
-					Unit:  units.Miles,
+					Plus:   true,
+					Length: units.Length{Value: 6, Unit: units.Miles},
 				},
 				Wind: Wind{
 					Direction: Direction{
 						Variable: true,
 					},
-					Speed: 3,
-					Unit:  units.Knots,
+					Speed: units.Velocity{Value: 3, Unit: units.Knots},
 				},
 				SkyCondition: []SkyCondition{
 					{
 						Type:     Broken,
-						Altitude: 2500,
+						Altitude: units.HeightFeet(2500),
 					},
 				},
 			},
 			{
 				Type: From,
+				Raw:  "FM221000 VRB03KT P6SM OVC025",
 				Valid: ValidPair{
 					From: time.Date(2023, time.August, 22, 10, 0, 0, 0, time.UTC),
 				},
 				Visibility: Visibility{
-					Plus:  true,
-					Value: 6,
-					Unit:  units.Miles,
+					Plus:   true,
+					Length: units.Length{Value: 6, Unit: units.Miles},
 				},
 				Wind: Wind{
 					Direction: Direction{
 						Variable: true,
 					},
-					Speed: 3,
-					Unit:  units.Knots,
+					Speed: units.Velocity{Value: 3, Unit: units.Knots},
 				},
 				SkyCondition: []SkyCondition{
 					{
 						Type:     Overcast,
-						Altitude: 2500,
+						Altitude: units.HeightFeet(2500),
 					},
 				},
 			},
 			{
 				Type: From,
+				Raw:  "FM221700 26006KT P6SM BKN025",
 				Valid: ValidPair{
 					From: time.Date(2023, time.August, 22, 17, 0, 0, 0, time.UTC),
 				},
 				Visibility: Visibility{
-					Plus:  true,
-					Value: 6,
-					Unit:  units.Miles,
+					Plus:   true,
+					Length: units.Length{Value: 6, Unit: units.Miles},
 				},
 				Wind: Wind{
 					Direction: Direction{
 						Value: 260,
 					},
-					Speed: 6,
-					Unit:  units.Knots,
+					Speed: units.Velocity{Value: 6, Unit: units.Knots},
 				},
 				SkyCondition: []SkyCondition{
 					{
 						Type:     Broken,
-						Altitude: 2500,
+						Altitude: units.HeightFeet(2500),
 					},
 				},
 			},
 			{
 				Type: From,
+				Raw:  "FM222000 26012KT P6SM SCT030",
 				Valid: ValidPair{
 					From: time.Date(2023, time.August, 22, 20, 0, 0, 0, time.UTC),
 				},
 				Visibility: Visibility{
-					Plus:  true,
-					Value: 6,
-					Unit:  units.Miles,
+					Plus:   true,
+					Length: units.Length{Value: 6, Unit: units.Miles},
 				},
 				Wind: Wind{
 					Direction: Direction{
 						Value: 260,
 					},
-					Speed: 12,
-					Unit:  units.Knots,
+					Speed: units.Velocity{Value: 12, Unit: units.Knots},
 				},
 				SkyCondition: []SkyCondition{
 					{
 						Type:     Scattered,
-						Altitude: 3000,
+						Altitude: units.HeightFeet(3000),
 					},
 				},
 			},
@@ -199,6 +194,10 @@ func TestKLAX(t *testing.T) {
 	if diff := deep.Equal(fc, expected); diff != nil {
 		t.Error(diff)
 	}
+
+	if s := fc.String(); s != data {
+		t.Errorf("Encoded forecast doesn't match input:\n%s", s)
+	}
 }
 
 func TestZGSZ(t *testing.T) {
@@ -207,6 +206,8 @@ func TestZGSZ(t *testing.T) {
   TEMPO 2204/2208 TSRA SCT020 FEW023CB`
 
 	expected := &Forecast{
+		Raw:        data,
+		Header:     true,
 		ReportType: Amended,
 		Identifier: "ZGSZ",
 		Airport: airports.Airport{
@@ -228,37 +229,38 @@ func TestZGSZ(t *testing.T) {
 			Duration: time.Duration(86400000000000),
 		},
 		Visibility: Visibility{
-			Value: 8000,
-			Unit:  units.Meters,
+			Length: units.Length{Value: 8000, Unit: units.Meters},
 		},
 		Wind: Wind{
 			Direction: Direction{
 				Value: 180,
 			},
-			Speed: 4,
-			Unit:  units.MetersPerSecond,
+			Speed: units.Velocity{Value: 4, Unit: units.MetersPerSecond},
 		},
 		SkyCondition: []SkyCondition{
 			{
 				Type:     Scattered,
-				Altitude: 2000,
+				Altitude: units.HeightFeet(2000),
 			},
 		},
 		Temperature: []Temperature{
 			{
 				Type:  High,
 				Value: 32,
+				Unit:  units.Celsius,
 				Time:  time.Date(2023, time.August, 22, 6, 0, 0, 0, time.UTC),
 			},
 			{
 				Type:  Low,
 				Value: 28,
+				Unit:  units.Celsius,
 				Time:  time.Date(2023, time.August, 21, 22, 0, 0, 0, time.UTC),
 			},
 		},
 		Changes: []*Change{
 			{
 				Type: Temporary,
+				Raw:  "TEMPO 2120/2202 SHRA SCT020 FEW023CB",
 				Valid: ValidPair{
 					From:     time.Date(2023, time.August, 21, 20, 0, 0, 0, time.UTC),
 					To:       time.Date(2023, time.August, 22, 2, 0, 0, 0, time.UTC),
@@ -267,11 +269,11 @@ func TestZGSZ(t *testing.T) {
 				SkyCondition: []SkyCondition{
 					{
 						Type:     Scattered,
-						Altitude: 2000,
+						Altitude: units.HeightFeet(2000),
 					},
 					{
 						Type:      Few,
-						Altitude:  2300,
+						Altitude:  units.HeightFeet(2300),
 						CloudType: CumuloNimbus,
 					},
 				},
@@ -284,6 +286,7 @@ func TestZGSZ(t *testing.T) {
 			},
 			{
 				Type: Temporary,
+				Raw:  "TEMPO 2204/2208 TSRA SCT020 FEW023CB",
 				Valid: ValidPair{
 					From:     time.Date(2023, time.August, 22, 4, 0, 0, 0, time.UTC),
 					To:       time.Date(2023, time.August, 22, 8, 0, 0, 0, time.UTC),
@@ -292,11 +295,11 @@ func TestZGSZ(t *testing.T) {
 				SkyCondition: []SkyCondition{
 					{
 						Type:     Scattered,
-						Altitude: 2000,
+						Altitude: units.HeightFeet(2000),
 					},
 					{
 						Type:      Few,
-						Altitude:  2300,
+						Altitude:  units.HeightFeet(2300),
 						CloudType: CumuloNimbus,
 					},
 				},
@@ -321,6 +324,10 @@ func TestZGSZ(t *testing.T) {
 	if diff := deep.Equal(fc, expected); diff != nil {
 		t.Error(diff)
 	}
+
+	if s := fc.String(); s != data {
+		t.Errorf("Encoded forecast doesn't match input:\n%s", s)
+	}
 }
 
 func TestLFBD(t *testing.T) {
@@ -331,6 +338,8 @@ func TestLFBD(t *testing.T) {
   BECMG 2222/2224 24004KT`
 
 	expected := &Forecast{
+		Raw:        data,
+		Header:     true,
 		Identifier: "LFBD",
 		Airport: airports.Airport{
 			ICAO:      "LFBD",
@@ -354,24 +363,26 @@ func TestLFBD(t *testing.T) {
 			Direction: Direction{
 				Value: 310,
 			},
-			Speed: 10,
-			Unit:  units.Knots,
+			Speed: units.Velocity{Value: 10, Unit: units.Knots},
 		},
 		Temperature: []Temperature{
 			{
 				Type:  High,
 				Value: 37,
+				Unit:  units.Celsius,
 				Time:  time.Date(2023, time.August, 22, 14, 0, 0, 0, time.UTC),
 			},
 			{
 				Type:  Low,
 				Value: 22,
+				Unit:  units.Celsius,
 				Time:  time.Date(2023, time.August, 22, 5, 0, 0, 0, time.UTC),
 			},
 		},
 		Changes: []*Change{
 			{
 				Type: Becoming,
+				Raw:  "BECMG 2118/2120 32004KT",
 				Valid: ValidPair{
 					From:     time.Date(2023, time.August, 21, 18, 0, 0, 0, time.UTC),
 					To:       time.Date(2023, time.August, 21, 20, 0, 0, 0, time.UTC),
@@ -381,12 +392,12 @@ func TestLFBD(t *testing.T) {
 					Direction: Direction{
 						Value: 320,
 					},
-					Speed: 4,
-					Unit:  units.Knots,
+					Speed: units.Velocity{Value: 4, Unit: units.Knots},
 				},
 			},
 			{
 				Type: Becoming,
+				Raw:  "BECMG 2200/2202 26005KT",
 				Valid: ValidPair{
 					From:     time.Date(2023, time.August, 22, 0, 0, 0, 0, time.UTC),
 					To:       time.Date(2023, time.August, 22, 2, 0, 0, 0, time.UTC),
@@ -396,12 +407,12 @@ func TestLFBD(t *testing.T) {
 					Direction: Direction{
 						Value: 260,
 					},
-					Speed: 5,
-					Unit:  units.Knots,
+					Speed: units.Velocity{Value: 5, Unit: units.Knots},
 				},
 			},
 			{
 				Type: Becoming,
+				Raw:  "BECMG 2213/2215 32010KT",
 				Valid: ValidPair{
 					From:     time.Date(2023, time.August, 22, 13, 0, 0, 0, time.UTC),
 					To:       time.Date(2023, time.August, 22, 15, 0, 0, 0, time.UTC),
@@ -411,12 +422,12 @@ func TestLFBD(t *testing.T) {
 					Direction: Direction{
 						Value: 320,
 					},
-					Speed: 10,
-					Unit:  units.Knots,
+					Speed: units.Velocity{Value: 10, Unit: units.Knots},
 				},
 			},
 			{
 				Type: Becoming,
+				Raw:  "BECMG 2222/2224 24004KT",
 				Valid: ValidPair{
 					From:     time.Date(2023, time.August, 22, 22, 0, 0, 0, time.UTC),
 					To:       time.Date(2023, time.August, 23, 0, 0, 0, 0, time.UTC),
@@ -426,8 +437,7 @@ func TestLFBD(t *testing.T) {
 					Direction: Direction{
 						Value: 240,
 					},
-					Speed: 4,
-					Unit:  units.Knots,
+					Speed: units.Velocity{Value: 4, Unit: units.Knots},
 				},
 			},
 		},
@@ -447,6 +457,10 @@ func TestLFBD(t *testing.T) {
 	if diff := deep.Equal(fc, expected); diff != nil {
 		t.Error(diff)
 	}
+
+	if s := fc.String(); s != data {
+		t.Errorf("Encoded forecast doesn't match input:\n%s", s)
+	}
 }
 
 func TestUUEE(t *testing.T) {
@@ -459,6 +473,8 @@ func TestUUEE(t *testing.T) {
   TEMPO 2209/2218 -TSRA BKN020CB`
 
 	expected := &Forecast{
+		Raw:        data,
+		Header:     true,
 		Identifier: "UUEE",
 		Airport: airports.Airport{
 			ICAO:      "UUEE",
@@ -479,37 +495,38 @@ func TestUUEE(t *testing.T) {
 			Duration: time.Duration(86400000000000),
 		},
 		Visibility: Visibility{
-			Value: 9999,
-			Unit:  units.Meters,
+			Length: units.Length{Value: 9999, Unit: units.Meters},
 		},
 		Wind: Wind{
 			Direction: Direction{
 				Variable: true,
 			},
-			Speed: 1,
-			Unit:  units.MetersPerSecond,
+			Speed: units.Velocity{Value: 1, Unit: units.MetersPerSecond},
 		},
 		SkyCondition: []SkyCondition{
 			{
 				Type:     Scattered,
-				Altitude: 3000,
+				Altitude: units.HeightFeet(3000),
 			},
 		},
 		Temperature: []Temperature{
 			{
 				Type:  High,
 				Value: 20,
+				Unit:  units.Celsius,
 				Time:  time.Date(2023, time.August, 22, 12, 0, 0, 0, time.UTC),
 			},
 			{
 				Type:  Low,
 				Value: 12,
+				Unit:  units.Celsius,
 				Time:  time.Date(2023, time.August, 22, 2, 0, 0, 0, time.UTC),
 			},
 		},
 		Changes: []*Change{
 			{
 				Type: Temporary,
+				Raw:  "TEMPO 2121/2204 BKN004",
 				Valid: ValidPair{
 					From:     time.Date(2023, time.August, 21, 21, 0, 0, 0, time.UTC),
 					To:       time.Date(2023, time.August, 22, 4, 0, 0, 0, time.UTC),
@@ -518,20 +535,20 @@ func TestUUEE(t *testing.T) {
 				SkyCondition: []SkyCondition{
 					{
 						Type:     Broken,
-						Altitude: 400,
+						Altitude: units.HeightFeet(400),
 					},
 				},
 			},
 			{
 				Type: Temporary,
+				Raw:  "PROB40\n  TEMPO 2121/2204 0300 FG",
 				Valid: ValidPair{
 					From:     time.Date(2023, time.August, 21, 21, 0, 0, 0, time.UTC),
 					To:       time.Date(2023, time.August, 22, 4, 0, 0, 0, time.UTC),
 					Duration: time.Duration(25200000000000),
 				},
 				Visibility: Visibility{
-					Value: 300,
-					Unit:  units.Meters,
+					Length: units.Length{Value: 300, Unit: units.Meters},
 				},
 				Weather: []Weather{
 					{
@@ -542,6 +559,7 @@ func TestUUEE(t *testing.T) {
 			},
 			{
 				Type: Becoming,
+				Raw:  "BECMG 2204/2206 24006MPS",
 				Valid: ValidPair{
 					From:     time.Date(2023, time.August, 22, 4, 0, 0, 0, time.UTC),
 					To:       time.Date(2023, time.August, 22, 6, 0, 0, 0, time.UTC),
@@ -551,12 +569,12 @@ func TestUUEE(t *testing.T) {
 					Direction: Direction{
 						Value: 240,
 					},
-					Speed: 6,
-					Unit:  units.MetersPerSecond,
+					Speed: units.Velocity{Value: 6, Unit: units.MetersPerSecond},
 				},
 			},
 			{
 				Type: Temporary,
+				Raw:  "PROB40\n  TEMPO 2209/2218 -TSRA BKN020CB",
 				Valid: ValidPair{
 					From:     time.Date(2023, time.August, 22, 9, 0, 0, 0, time.UTC),
 					To:       time.Date(2023, time.August, 22, 18, 0, 0, 0, time.UTC),
@@ -565,7 +583,7 @@ func TestUUEE(t *testing.T) {
 				SkyCondition: []SkyCondition{
 					{
 						Type:      Broken,
-						Altitude:  2000,
+						Altitude:  units.HeightFeet(2000),
 						CloudType: CumuloNimbus,
 					},
 				},
@@ -592,6 +610,10 @@ func TestUUEE(t *testing.T) {
 	if diff := deep.Equal(fc, expected); diff != nil {
 		t.Error(diff)
 	}
+
+	if s := fc.String(); s != data {
+		t.Errorf("Encoded forecast doesn't match input:\n%s", s)
+	}
 }
 
 func TestEGLL(t *testing.T) {
@@ -602,6 +624,8 @@ func TestEGLL(t *testing.T) {
   BECMG 2207/2210 SCT025`
 
 	expected := &Forecast{
+		Raw:        data,
+		Header:     true,
 		Identifier: "EGLL",
 		Airport: airports.Airport{
 			ICAO:      "EGLL",
@@ -622,25 +646,24 @@ func TestEGLL(t *testing.T) {
 			Duration: time.Duration(108000000000000),
 		},
 		Visibility: Visibility{
-			Value: 9999,
-			Unit:  units.Meters,
+			Length: units.Length{Value: 9999, Unit: units.Meters},
 		},
 		Wind: Wind{
 			Direction: Direction{
 				Value: 220,
 			},
-			Speed: 8,
-			Unit:  units.Knots,
+			Speed: units.Velocity{Value: 8, Unit: units.Knots},
 		},
 		SkyCondition: []SkyCondition{
 			{
 				Type:     Few,
-				Altitude: 4000,
+				Altitude: units.HeightFeet(4000),
 			},
 		},
 		Changes: []*Change{
 			{
 				Type: Becoming,
+				Raw:  "BECMG 2201/2204 BKN007",
 				Valid: ValidPair{
 					From:     time.Date(2023, time.August, 22, 1, 0, 0, 0, time.UTC),
 					To:       time.Date(2023, time.August, 22, 4, 0, 0, 0, time.UTC),
@@ -649,31 +672,32 @@ func TestEGLL(t *testing.T) {
 				SkyCondition: []SkyCondition{
 					{
 						Type:     Broken,
-						Altitude: 700,
+						Altitude: units.HeightFeet(700),
 					},
 				},
 			},
 			{
 				Type: Temporary,
+				Raw:  "PROB30\n  TEMPO 2202/2206 8000 BKN004",
 				Valid: ValidPair{
 					From:     time.Date(2023, time.August, 22, 2, 0, 0, 0, time.UTC),
 					To:       time.Date(2023, time.August, 22, 6, 0, 0, 0, time.UTC),
 					Duration: time.Duration(14400000000000),
 				},
 				Visibility: Visibility{
-					Value: 8000,
-					Unit:  units.Meters,
+					Length: units.Length{Value: 8000, Unit: units.Meters},
 				},
 				SkyCondition: []SkyCondition{
 					{
 						Type:     Broken,
-						Altitude: 400,
+						Altitude: units.HeightFeet(400),
 					},
 				},
 				Probability: 30,
 			},
 			{
 				Type: Becoming,
+				Raw:  "BECMG 2207/2210 SCT025",
 				Valid: ValidPair{
 					From:     time.Date(2023, time.August, 22, 7, 0, 0, 0, time.UTC),
 					To:       time.Date(2023, time.August, 22, 10, 0, 0, 0, time.UTC),
@@ -682,7 +706,7 @@ func TestEGLL(t *testing.T) {
 				SkyCondition: []SkyCondition{
 					{
 						Type:     Scattered,
-						Altitude: 2500,
+						Altitude: units.HeightFeet(2500),
 					},
 				},
 			},
@@ -700,4 +724,151 @@ func TestEGLL(t *testing.T) {
 	if diff := deep.Equal(fc, expected); diff != nil {
 		t.Error(diff)
 	}
+
+	if s := fc.String(); s != data {
+		t.Errorf("Encoded forecast doesn't match input:\n%s", s)
+	}
+}
+
+func TestEncodeProbabilityChange(t *testing.T) {
+	const data = `TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040
+  PROB30 TEMPO 2202/2206 8000 BKN004
+  BECMG 2207/2210 SCT025`
+
+	fc, err := DecodeWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	if s := fc.String(); s != data {
+		t.Errorf("Encoded forecast doesn't match input:\n%s", s)
+	}
+
+	// Changes that weren't decoded from text have the PROB group on the same
+	// line, even if their raw text has it on its own line.
+	ch := &Change{Type: Temporary, Probability: 40, Valid: fc.Changes[0].Valid, Visibility: fc.Visibility, Raw: "PROB40\n  TEMPO 2202/2206 9999"}
+	if s := ch.String(); s != "PROB40 TEMPO 2202/2206 9999" {
+		t.Errorf("Unexpected encoded change: %q", s)
+	}
+}
+
+func TestEncodeGroupOrder(t *testing.T) {
+	const data = `TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040
+  PROB30 2202/2206 BKN010
+  TEMPO 2202/2206 8000 BKN004
+  BECMG 2118/2120 SCT025
+  PROB40
+  TEMPO 2120/2122 4000 RA`
+
+	fc, err := DecodeWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	if s := fc.String(); s != data {
+		t.Errorf("Encoded forecast doesn't keep the order of the groups:\n%s", s)
+	}
+
+	// Groups that weren't decoded from text are encoded in chronological order
+	fc.Changes = []*Change{
+		{Type: Becoming, Valid: fc.Changes[1].Valid, Visibility: fc.Visibility},
+		{Type: Temporary, Valid: fc.Changes[0].Valid, Visibility: fc.Visibility},
+	}
+	fc.Probabilities = []*Probability{{Value: 30, Valid: fc.Probabilities[0].Valid, Visibility: fc.Visibility}}
+	expected := `TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040
+  BECMG 2118/2120 9999
+  TEMPO 2202/2206 9999
+  PROB30 2202/2206 9999`
+	if s := fc.String(); s != expected {
+		t.Errorf("Unexpected encoded forecast:\n%s", s)
+	}
+}
+
+// TestChangesJSON checks the JSON encoding of decoded changes, so that
+// groups a change doesn't have aren't encoded as zero values.
+func TestChangesJSON(t *testing.T) {
+	const data = `TAF KLAX 211130Z 2112/2218 26012KT P6SM FEW035
+  BECMG 2114/2116 SKC
+  FM211800 00000KT P6SM BKN010`
+
+	fc, err := DecodeWithOptions(strings.NewReader(data), Options{
+		Month: time.August,
+		Year:  2023,
+	})
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+
+	const expected = `[
+	{
+		"type": "Becoming",
+		"valid": {
+			"from": "2023-08-21T14:00:00Z",
+			"to": "2023-08-21T16:00:00Z",
+			"duration": 7200000000000
+		},
+		"visibility": {},
+		"wind": {
+			"direction": {}
+		},
+		"wind_shear": {
+			"direction": {}
+		},
+		"sky_condition": [
+			{
+				"type": "SkyClear"
+			}
+		],
+		"altimeter": {},
+		"raw": "BECMG 2114/2116 SKC"
+	},
+	{
+		"type": "From",
+		"valid": {
+			"from": "2023-08-21T18:00:00Z",
+			"to": "0001-01-01T00:00:00Z"
+		},
+		"visibility": {
+			"plus": true,
+			"value": 6,
+			"unit": "Miles"
+		},
+		"wind": {
+			"direction": {},
+			"speed": {
+				"value": 0,
+				"unit": "Knots"
+			}
+		},
+		"wind_shear": {
+			"direction": {}
+		},
+		"sky_condition": [
+			{
+				"type": "Broken",
+				"altitude": {
+					"value": 1000,
+					"unit": "Feet"
+				}
+			}
+		],
+		"altimeter": {},
+		"raw": "FM211800 00000KT P6SM BKN010"
+	}
+]`
+
+	out, err := json.MarshalIndent(fc.Changes, "", "\t")
+	if err != nil {
+		t.Fatalf("Error encoding changes: %s", err)
+	}
+
+	if string(out) != expected {
+		t.Errorf("Unexpected JSON:\n%s", out)
+	}
 }
@@ -1,7 +1,9 @@
 package taf
 
 import (
-	"strings"
+	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
 	"go.elara.ws/taf/internal/parser"
@@ -12,25 +14,81 @@ const (
 	ValidFormat = "0215"
 )
 
-func parseTime(s string, m time.Month, year int) (time.Time, error) {
-	t, err := time.Parse(TimeFormat, s)
+// reference returns the time that day/hour groups should be resolved
+// relative to until the issue time of the report is known.
+func (opts Options) reference() time.Time {
+	if !opts.Reference.IsZero() {
+		return opts.Reference.UTC()
+	}
+
+	now := time.Now().UTC()
+	if opts.Year == 0 && opts.Month == 0 {
+		return now
+	}
+
+	year, month := opts.Year, opts.Month
+	if year == 0 {
+		year = now.Year()
+	}
+	if month == 0 {
+		month = now.Month()
+	}
+	// The middle of the month is roughly equally close to both ends of it
+	return time.Date(year, month, 16, 12, 0, 0, 0, time.UTC)
+}
+
+// parseIssueTime parses the DDHHMM time at which a report was issued.
+// If a reference time isn't set but a year or month is, the time is
+// resolved within that month. Otherwise, it's resolved to the closest
+// date to the reference time.
+func parseIssueTime(s string, opts Options) (time.Time, error) {
+	if !opts.Reference.IsZero() || (opts.Year == 0 && opts.Month == 0) {
+		return parseTime(s, opts.reference())
+	}
+
+	day, hour, minute, err := splitTime(s, 3)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	ref := opts.reference()
+	if day > daysIn(ref.Year(), ref.Month()) {
+		return time.Time{}, fmt.Errorf("day %d doesn't exist in %s %d", day, ref.Month(), ref.Year())
+	}
+	return time.Date(ref.Year(), ref.Month(), day, hour, minute, 0, 0, time.UTC), nil
+}
+
+// parseTime parses a DDHHMM time, resolving it to the closest date to ref.
+func parseTime(s string, ref time.Time) (time.Time, error) {
+	day, hour, minute, err := splitTime(s, 3)
 	if err != nil {
 		return time.Time{}, err
 	}
-	return t.AddDate(year, int(m)-1, 0), nil
+	return resolveTime(day, hour, minute, ref)
 }
 
-func parseValid(v *parser.ValidPair, m time.Month, year int) (ValidPair, error) {
-	start, err := parseValidTime(v.Start, m, year)
+// parseValid parses a DDHH/DDHH validity period. The start is resolved to
+// the closest date to ref, and the end is resolved to the closest date
+// to the start, so that periods crossing the end of a month work correctly.
+func parseValid(v *parser.ValidPair, ref time.Time) (ValidPair, error) {
+	if v == nil {
+		return ValidPair{}, errors.New("missing validity period")
+	}
+
+	start, err := parseValidTime(v.Start, ref)
 	if err != nil {
 		return ValidPair{}, err
 	}
 
-	end, err := parseValidTime(v.End, m, year)
+	end, err := parseValidTime(v.End, start)
 	if err != nil {
 		return ValidPair{}, err
 	}
 
+	if end.Before(start) {
+		return ValidPair{}, fmt.Errorf("period ends (%s) before it starts (%s)", v.End, v.Start)
+	}
+
 	return ValidPair{
 		From:     start,
 		To:       end,
@@ -38,19 +96,96 @@ func parseValid(v *parser.ValidPair, m time.Month, year int) (ValidPair, error)
 	}, nil
 }
 
-func parseValidTime(s string, m time.Month, year int) (time.Time, error) {
-	addDays := 0
-	// Go doesn't know what to do with hour 24,
-	// so we set it to 00 the next day
-	if strings.HasSuffix(s, "24") {
-		s = s[:2] + "00"
-		addDays = 1
-	}
-
-	t, err := time.Parse(ValidFormat, s)
+// parseValidTime parses a DDHH time, resolving it to the closest date to ref.
+func parseValidTime(s string, ref time.Time) (time.Time, error) {
+	day, hour, _, err := splitTime(s, 2)
 	if err != nil {
 		return time.Time{}, err
 	}
+	return resolveTime(day, hour, 0, ref)
+}
+
+// parseHourTime parses an HH time, resolving it to the first
+// occurrence of that hour at or after the hour of ref.
+func parseHourTime(s string, ref time.Time) (time.Time, error) {
+	hour, err := strconv.Atoi(s)
+	if err != nil || hour > 24 {
+		return time.Time{}, fmt.Errorf("invalid time %q", s)
+	}
+
+	// time.Date normalizes hour 24 to midnight of the next day
+	t := time.Date(ref.Year(), ref.Month(), ref.Day(), hour, 0, 0, 0, time.UTC)
+	if t.Before(ref.Truncate(time.Hour)) {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// splitTime splits a time string containing n two-digit fields
+// (day, hour, and optionally minute) and checks that they're in range.
+// Hour 24 is allowed to indicate midnight at the end of the day.
+func splitTime(s string, n int) (day, hour, minute int, err error) {
+	if len(s) != n*2 {
+		return 0, 0, 0, fmt.Errorf("invalid time %q", s)
+	}
+
+	fields := make([]int, 3)
+	for i := 0; i < n; i++ {
+		fields[i], err = strconv.Atoi(s[i*2 : i*2+2])
+		if err != nil {
+			return 0, 0, 0, fmt.Errorf("invalid time %q", s)
+		}
+	}
+	day, hour, minute = fields[0], fields[1], fields[2]
+
+	switch {
+	case day < 1 || day > 31:
+		return 0, 0, 0, fmt.Errorf("invalid day in time %q", s)
+	case hour > 24, hour == 24 && minute != 0:
+		return 0, 0, 0, fmt.Errorf("invalid hour in time %q", s)
+	case minute > 59:
+		return 0, 0, 0, fmt.Errorf("invalid minute in time %q", s)
+	}
+
+	return day, hour, minute, nil
+}
+
+// resolveTime returns the time closest to ref that falls on the given
+// day of a month, looking at the month of ref and the months before and
+// after it. Months that don't have the given day are skipped.
+func resolveTime(day, hour, minute int, ref time.Time) (time.Time, error) {
+	var (
+		out  time.Time
+		best time.Duration = -1
+	)
+
+	for offset := -1; offset <= 1; offset++ {
+		month := time.Date(ref.Year(), ref.Month()+time.Month(offset), 1, 0, 0, 0, 0, time.UTC)
+		if day > daysIn(month.Year(), month.Month()) {
+			continue
+		}
+
+		// time.Date normalizes hour 24 to midnight of the next day
+		t := time.Date(month.Year(), month.Month(), day, hour, minute, 0, 0, time.UTC)
+
+		dist := t.Sub(ref)
+		if dist < 0 {
+			dist = -dist
+		}
+
+		if best < 0 || dist < best {
+			out, best = t, dist
+		}
+	}
+
+	if best < 0 {
+		return time.Time{}, errors.New("no matching date found")
+	}
+
+	return out, nil
+}
 
-	return t.AddDate(year, int(m)-1, addDays), nil
+// daysIn returns the number of days in the given month
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
 }
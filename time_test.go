@@ -0,0 +1,84 @@
+package taf
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRollover(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    string
+		opts    Options
+		publish time.Time
+		valid   ValidPair
+		change  time.Time
+		temp    time.Time
+	}{
+		{
+			name:    "month",
+			data:    "KLAX 311130Z 3112/0118 26012KT P6SM FEW035 TX25/0100Z\n  FM010600 25010KT P6SM SCT040",
+			opts:    Options{Reference: time.Date(2024, time.February, 1, 3, 0, 0, 0, time.UTC)},
+			publish: time.Date(2024, time.January, 31, 11, 30, 0, 0, time.UTC),
+			valid: ValidPair{
+				From:     time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC),
+				To:       time.Date(2024, time.February, 1, 18, 0, 0, 0, time.UTC),
+				Duration: 30 * time.Hour,
+			},
+			change: time.Date(2024, time.February, 1, 6, 0, 0, 0, time.UTC),
+			temp:   time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "year",
+			data:    "KLAX 311730Z 3118/0124 26012KT P6SM FEW035 TX25/3121Z\n  FM011200 25010KT P6SM SCT040",
+			opts:    Options{Year: 2023, Month: time.December},
+			publish: time.Date(2023, time.December, 31, 17, 30, 0, 0, time.UTC),
+			valid: ValidPair{
+				From:     time.Date(2023, time.December, 31, 18, 0, 0, 0, time.UTC),
+				To:       time.Date(2024, time.January, 2, 0, 0, 0, 0, time.UTC),
+				Duration: 30 * time.Hour,
+			},
+			change: time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC),
+			temp:   time.Date(2023, time.December, 31, 21, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "30-day month",
+			data:    "KLAX 291730Z 2918/3024 26012KT P6SM FEW035 TX25/3024Z\n  FM301200 25010KT P6SM SCT040",
+			opts:    Options{Reference: time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC)},
+			publish: time.Date(2023, time.June, 29, 17, 30, 0, 0, time.UTC),
+			valid: ValidPair{
+				From:     time.Date(2023, time.June, 29, 18, 0, 0, 0, time.UTC),
+				To:       time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC),
+				Duration: 30 * time.Hour,
+			},
+			change: time.Date(2023, time.June, 30, 12, 0, 0, 0, time.UTC),
+			temp:   time.Date(2023, time.July, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fc, err := DecodeWithOptions(strings.NewReader(tc.data), tc.opts)
+			if err != nil {
+				t.Fatalf("Error during parsing: %s", err)
+			}
+
+			if !fc.PublishTime.Equal(tc.publish) {
+				t.Errorf("Expected publish time %s, got %s", tc.publish, fc.PublishTime)
+			}
+
+			if fc.Valid != tc.valid {
+				t.Errorf("Expected validity %v, got %v", tc.valid, fc.Valid)
+			}
+
+			if !fc.Changes[0].Valid.From.Equal(tc.change) {
+				t.Errorf("Expected change time %s, got %s", tc.change, fc.Changes[0].Valid.From)
+			}
+
+			if !fc.Temperature[0].Time.Equal(tc.temp) {
+				t.Errorf("Expected temperature time %s, got %s", tc.temp, fc.Temperature[0].Time)
+			}
+		})
+	}
+}
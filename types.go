@@ -1,6 +1,7 @@
 package taf
 
 import (
+	"encoding/json"
 	"time"
 
 	"go.elara.ws/taf/airports"
@@ -9,6 +10,9 @@ import (
 
 // Forecast represents a Terminal Aerodrome Forecast (TAF) weather report for a specific airport.
 type Forecast struct {
+	// Header indicates whether the report started with a "TAF" header.
+	Header bool `json:"header,omitempty"`
+
 	// ReportType represents the type of report this forecast describes.
 	ReportType ReportType `json:"report_type,omitempty"`
 
@@ -30,6 +34,9 @@ type Forecast struct {
 	// Wind describes the projected wind conditions.
 	Wind Wind `json:"wind,omitempty"`
 
+	// WindShear describes non-convective low-level wind shear, if any is expected.
+	WindShear Wind `json:"wind_shear,omitempty"`
+
 	// SkyCondition lists the expected sky conditions.
 	SkyCondition []SkyCondition `json:"sky_condition,omitempty"`
 
@@ -39,6 +46,15 @@ type Forecast struct {
 	// Weather lists information about the expected weather conditions.
 	Weather []Weather `json:"weather,omitempty"`
 
+	// Icing lists the expected layers of icing.
+	Icing []Icing `json:"icing,omitempty"`
+
+	// Turbulence lists the expected layers of turbulence.
+	Turbulence []Turbulence `json:"turbulence,omitempty"`
+
+	// Altimeter holds the lowest expected altimeter setting.
+	Altimeter Altimeter `json:"altimeter,omitempty"`
+
 	// Probabilities contains the probabilities for potential conditions.
 	Probabilities []*Probability `json:"probabilities,omitempty"`
 
@@ -50,6 +66,23 @@ type Forecast struct {
 
 	// Remark contains remarks from the forecast.
 	Remark string `json:"remark,omitempty"`
+
+	// Warnings lists the problems found while decoding the forecast in lenient mode.
+	Warnings []Problem `json:"warnings,omitempty"`
+
+	// Unparsed lists the groups that were skipped while decoding the forecast in lenient mode.
+	Unparsed []string `json:"unparsed,omitempty"`
+
+	// Line is the line on which the report started in the stream it was
+	// decoded from, starting at 1. It's only set by Decoder and DecodeAll.
+	Line int `json:"line,omitempty"`
+
+	// Raw contains the original text of the report.
+	Raw string `json:"raw,omitempty"`
+
+	// Spans lists the parts of the original text that the base forecast
+	// was decoded from. It's only set if Options.Spans is set.
+	Spans []Span `json:"spans,omitempty"`
 }
 
 // Change represents a change in weather conditions within a forecast.
@@ -66,6 +99,9 @@ type Change struct {
 	// Wind describes the projected wind conditions.
 	Wind Wind `json:"wind,omitempty"`
 
+	// WindShear describes non-convective low-level wind shear, if any is expected.
+	WindShear Wind `json:"wind_shear,omitempty"`
+
 	// SkyCondition lists the expected sky conditions.
 	SkyCondition []SkyCondition `json:"sky_condition,omitempty"`
 
@@ -75,11 +111,36 @@ type Change struct {
 	// Weather lists information about the expected weather conditions.
 	Weather []Weather `json:"weather,omitempty"`
 
+	// Icing lists the expected layers of icing.
+	Icing []Icing `json:"icing,omitempty"`
+
+	// Turbulence lists the expected layers of turbulence.
+	Turbulence []Turbulence `json:"turbulence,omitempty"`
+
+	// Altimeter holds the lowest expected altimeter setting.
+	Altimeter Altimeter `json:"altimeter,omitempty"`
+
 	// Flags contains special flags associated with the change.
 	Flags []Flag `json:"flags,omitempty"`
 
 	// Probability indicates the percent chance of this change occurring.
 	Probability int `json:"probability,omitempty"`
+
+	// Raw contains the original text of the change, including
+	// the PROB group before it, if there is one.
+	Raw string `json:"raw,omitempty"`
+
+	// Spans lists the parts of the original text that the change
+	// was decoded from. It's only set if Options.Spans is set.
+	Spans []Span `json:"spans,omitempty"`
+
+	// order is the position of the change among the change and
+	// probability groups of the report, starting at 1. It's zero
+	// if the change wasn't decoded from text.
+	order int
+	// probOnOwnLine is set if the PROB group of the change
+	// was on its own line in the original text.
+	probOnOwnLine bool
 }
 
 // Probability represents the probability of potential conditions occurring within a forecast.
@@ -96,6 +157,9 @@ type Probability struct {
 	// Wind describes the projected wind conditions.
 	Wind Wind `json:"wind,omitempty"`
 
+	// WindShear describes non-convective low-level wind shear, if any is expected.
+	WindShear Wind `json:"wind_shear,omitempty"`
+
 	// SkyCondition lists the expected sky conditions.
 	SkyCondition []SkyCondition `json:"sky_condition,omitempty"`
 
@@ -105,8 +169,48 @@ type Probability struct {
 	// Weather lists information about the expected weather conditions.
 	Weather []Weather `json:"weather,omitempty"`
 
+	// Icing lists the expected layers of icing.
+	Icing []Icing `json:"icing,omitempty"`
+
+	// Turbulence lists the expected layers of turbulence.
+	Turbulence []Turbulence `json:"turbulence,omitempty"`
+
+	// Altimeter holds the lowest expected altimeter setting.
+	Altimeter Altimeter `json:"altimeter,omitempty"`
+
 	// Flags contains special flags associated with the potential conditions.
 	Flags []Flag `json:"flags,omitempty"`
+
+	// Raw contains the original text of the probability group.
+	Raw string `json:"raw,omitempty"`
+
+	// Spans lists the parts of the original text that the probability
+	// group was decoded from. It's only set if Options.Spans is set.
+	Spans []Span `json:"spans,omitempty"`
+
+	// order is the position of the probability group among the change
+	// and probability groups of the report, starting at 1. It's zero
+	// if the probability group wasn't decoded from text.
+	order int
+}
+
+// Span identifies the part of the original text that a field was decoded from.
+type Span struct {
+	// Field is the JSON name of the field that was decoded from the text,
+	// with the index of the element for lists (e.g. "sky_condition[1]").
+	Field string `json:"field"`
+
+	// Offset is the byte offset of the text within the report.
+	Offset int `json:"offset"`
+
+	// Line is the line on which the text starts, starting at 1.
+	Line int `json:"line"`
+
+	// Column is the column at which the text starts, starting at 1.
+	Column int `json:"column"`
+
+	// Token contains the original text.
+	Token string `json:"token"`
 }
 
 // ValidPair represents a time interval for which weather data is valid.
@@ -126,11 +230,11 @@ type Visibility struct {
 	// Plus indicates whether visibility is expected to be greater than the specified value.
 	Plus bool `json:"plus,omitempty"`
 
-	// Value holds the visibility measurement. Its unit is determined by the Unit field.
-	Value float64 `json:"value,omitempty"`
+	// Minus indicates whether visibility is expected to be less than the specified value.
+	Minus bool `json:"minus,omitempty"`
 
-	// Unit specifies the unit of measurement for the visibility value.
-	Unit units.Distance `json:"unit,omitempty"`
+	// Length holds the visibility measurement and its unit.
+	units.Length
 }
 
 // ReportType represents different types of reports.
@@ -154,6 +258,7 @@ const (
 	Overcast           SkyConditionType = "Overcast"
 	VerticalVisibility SkyConditionType = "VerticalVisibility"
 	SkyClear           SkyConditionType = "SkyClear"
+	Clear              SkyConditionType = "Clear"
 )
 
 // CloudType represents different types of cloud formations.
@@ -170,29 +275,58 @@ type SkyCondition struct {
 	// Type specifies the nature of the expected sky condition.
 	Type SkyConditionType `json:"type,omitempty"`
 
-	// Altitude represents the altitude at which this sky condition is anticipated, in feet.
-	Altitude int `json:"altitude,omitempty"`
+	// Altitude represents the height above the ground at which this sky condition is anticipated.
+	Altitude units.Height `json:"altitude,omitempty"`
 
 	// CloudType defines the type of clouds expected in the sky.
 	CloudType CloudType `json:"cloud_type,omitempty"`
 }
 
+// MarshalJSON encodes the sky condition as JSON, leaving
+// out the altitude if it isn't set.
+func (sc SkyCondition) MarshalJSON() ([]byte, error) {
+	type skyCondition SkyCondition
+	return json.Marshal(struct {
+		skyCondition
+		Altitude *units.Height `json:"altitude,omitempty"`
+	}{skyCondition(sc), omitZero(sc.Altitude)})
+}
+
 // Wind represents wind-related information in a weather forecast.
 type Wind struct {
 	// Direction indicates the wind direction of the expected wind.
 	Direction Direction `json:"direction,omitempty"`
 
-	// WindShear specifies the altitude at which wind shear is expected.
-	WindShear int `json:"wind_shear,omitempty"`
+	// WindShear specifies the height at which wind shear is expected.
+	WindShear units.Height `json:"wind_shear,omitempty"`
 
-	// Speed represents the anticipated wind speed. The unit is determined by the Unit field.
-	Speed int `json:"speed,omitempty"`
+	// Speed represents the anticipated wind speed.
+	Speed units.Velocity `json:"speed,omitempty"`
 
-	// Gusts holds the projected gust speed. The unit is determined by the Unit field.
-	Gusts int `json:"gusts,omitempty"`
+	// Gusts holds the projected gust speed. Its value is zero if no gusts are expected.
+	Gusts units.Velocity `json:"gusts,omitempty"`
+}
 
-	// Unit denotes the unit of measurement for wind and gust speeds.
-	Unit units.Speed `json:"unit,omitempty"`
+// MarshalJSON encodes the wind as JSON, leaving out
+// the wind shear height, speed, and gusts if they aren't set.
+func (w Wind) MarshalJSON() ([]byte, error) {
+	type wind Wind
+	return json.Marshal(struct {
+		wind
+		WindShear *units.Height   `json:"wind_shear,omitempty"`
+		Speed     *units.Velocity `json:"speed,omitempty"`
+		Gusts     *units.Velocity `json:"gusts,omitempty"`
+	}{wind(w), omitZero(w.WindShear), omitZero(w.Speed), omitZero(w.Gusts)})
+}
+
+// omitZero returns a pointer to v, or nil if v is the zero value,
+// so that omitempty can be used for struct fields.
+func omitZero[T comparable](v T) *T {
+	var zero T
+	if v == zero {
+		return nil
+	}
+	return &v
 }
 
 // Direction describes the wind direction, which can be variable.
@@ -308,9 +442,13 @@ type Temperature struct {
 	// Type specifies if this temperature is a high or low value.
 	Type TemperatureType `json:"type,omitempty"`
 
-	// Value holds the anticipated temperature in degrees Celsius.
+	// Value holds the anticipated temperature. Its unit is determined by the Unit field.
 	Value int `json:"value,omitempty"`
 
+	// Unit specifies the unit of the temperature. If it's empty,
+	// the temperature is in degrees Celsius.
+	Unit units.Temperature `json:"unit,omitempty"`
+
 	// Time indicates the expected time for this temperature.
 	Time time.Time `json:"time,omitempty"`
 }
@@ -336,4 +474,28 @@ const (
 	// CeilingAndVisibilityOK indicates that visibility is over 10km, that
 	// there are no significant clouds, and no significant weather
 	CeilingAndVisibilityOK Flag = "CeilingAndVisibilityOK"
+	// Automated indicates that a report was generated without human intervention
+	Automated Flag = "Automated"
+	// NoSignificantChange indicates that no significant changes are expected
+	NoSignificantChange Flag = "NoSignificantChange"
+	// NoSignificantWeather indicates that previously forecast weather is expected to end
+	NoSignificantWeather Flag = "NoSignificantWeather"
+	// NoSignificantCloud indicates that there are no clouds below 5000 feet,
+	// no cumulonimbus, and no towering cumulus, but CAVOK isn't appropriate
+	NoSignificantCloud Flag = "NoSignificantCloud"
+	// NoCloudDetected indicates that an automated station didn't detect any clouds
+	NoCloudDetected Flag = "NoCloudDetected"
+	// Cancelled indicates that a previously issued forecast has been cancelled
+	Cancelled Flag = "Cancelled"
+	// Missing indicates that a report is missing (NIL)
+	Missing Flag = "Missing"
 )
+
+// Altimeter represents an altimeter setting.
+type Altimeter struct {
+	// Value holds the altimeter setting. Its unit is determined by the Unit field.
+	Value float64 `json:"value,omitempty"`
+
+	// Unit specifies the unit of measurement for the altimeter setting.
+	Unit units.Pressure `json:"unit,omitempty"`
+}
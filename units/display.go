@@ -0,0 +1,28 @@
+package units
+
+import "go.elara.ws/taf/i18n"
+
+// DisplayName returns the name of the speed unit in the given locale
+func (s Speed) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "Speed", string(s))
+}
+
+// DisplayName returns the name of the distance unit in the given locale
+func (d Distance) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "Distance", string(d))
+}
+
+// DisplayName returns the name of the height unit in the given locale
+func (h HeightUnit) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "HeightUnit", string(h))
+}
+
+// DisplayName returns the name of the pressure unit in the given locale
+func (p Pressure) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "Pressure", string(p))
+}
+
+// DisplayName returns the name of the temperature unit in the given locale
+func (t Temperature) DisplayName(locale string) string {
+	return i18n.DisplayName(locale, "Temperature", string(t))
+}
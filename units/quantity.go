@@ -0,0 +1,196 @@
+package units
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Length represents a horizontal distance, such as a visibility.
+type Length struct {
+	// Value is the length in the unit determined by the Unit field.
+	Value float64 `json:"value,omitempty"`
+
+	// Unit is the unit of the length.
+	Unit Distance `json:"unit,omitempty"`
+}
+
+// In returns the length in the given unit
+func (l Length) In(unit Distance) float64 {
+	return l.Unit.Convert(unit, l.Value)
+}
+
+// Less checks whether l is shorter than other, regardless of their units
+func (l Length) Less(other Length) bool {
+	return l.In(Meters) < other.In(Meters)
+}
+
+// Max returns the longer of l and other
+func (l Length) Max(other Length) Length {
+	if l.Less(other) {
+		return other
+	}
+	return l
+}
+
+// String returns the length with the symbol of its unit, such as "1500 m"
+func (l Length) String() string {
+	return formatQuantity(l.Value, l.Unit.Symbol())
+}
+
+// Velocity represents a speed, such as a wind speed.
+type Velocity struct {
+	// Value is the speed in the unit determined by the Unit field.
+	Value float64 `json:"value"`
+
+	// Unit is the unit of the speed.
+	Unit Speed `json:"unit,omitempty"`
+}
+
+// In returns the speed in the given unit
+func (v Velocity) In(unit Speed) float64 {
+	return v.Unit.Convert(unit, v.Value)
+}
+
+// Less checks whether v is slower than other, regardless of their units
+func (v Velocity) Less(other Velocity) bool {
+	return v.In(MetersPerSecond) < other.In(MetersPerSecond)
+}
+
+// Max returns the faster of v and other
+func (v Velocity) Max(other Velocity) Velocity {
+	if v.Less(other) {
+		return other
+	}
+	return v
+}
+
+// String returns the speed with the symbol of its unit, such as "10 kt"
+func (v Velocity) String() string {
+	return formatQuantity(v.Value, v.Unit.Symbol())
+}
+
+// Height represents a vertical distance above the ground,
+// such as the base of a cloud layer.
+type Height struct {
+	// Value is the height in the unit determined by the Unit field.
+	Value float64 `json:"value"`
+
+	// Unit is the unit of the height.
+	Unit HeightUnit `json:"unit,omitempty"`
+}
+
+// HeightFeet returns a height of n feet
+func HeightFeet(n float64) Height {
+	return Height{Value: n, Unit: HeightInFeet}
+}
+
+// In returns the height in the given unit
+func (h Height) In(unit HeightUnit) float64 {
+	return h.Unit.Convert(unit, h.Value)
+}
+
+// Less checks whether h is lower than other, regardless of their units
+func (h Height) Less(other Height) bool {
+	return h.In(HeightInFeet) < other.In(HeightInFeet)
+}
+
+// Max returns the higher of h and other
+func (h Height) Max(other Height) Height {
+	if h.Less(other) {
+		return other
+	}
+	return h
+}
+
+// String returns the height with the symbol of its unit, such as "2000 ft".
+// Flight levels are written with the symbol first and three digits, such as "FL350".
+func (h Height) String() string {
+	if h.Unit == FlightLevels {
+		return fmt.Sprintf("FL%03d", int(math.Round(h.Value)))
+	}
+	return formatQuantity(h.Value, h.Unit.Symbol())
+}
+
+// Symbol returns the symbol of the speed unit, such as "kt"
+func (s Speed) Symbol() string {
+	switch s {
+	case MetersPerSecond:
+		return "m/s"
+	case KilometersPerHour:
+		return "km/h"
+	case Knots:
+		return "kt"
+	case MilesPerHour:
+		return "mph"
+	default:
+		return string(s)
+	}
+}
+
+// Symbol returns the symbol of the distance unit, such as "km"
+func (d Distance) Symbol() string {
+	switch d {
+	case Meters:
+		return "m"
+	case Kilometers:
+		return "km"
+	case Miles:
+		return "mi"
+	case Feet:
+		return "ft"
+	default:
+		return string(d)
+	}
+}
+
+// Symbol returns the symbol of the height unit, such as "ft"
+func (h HeightUnit) Symbol() string {
+	switch h {
+	case HeightInFeet:
+		return "ft"
+	case HeightInMeters:
+		return "m"
+	case FlightLevels:
+		return "FL"
+	default:
+		return string(h)
+	}
+}
+
+// Symbol returns the symbol of the pressure unit, such as "hPa"
+func (p Pressure) Symbol() string {
+	switch p {
+	case Hectopascals:
+		return "hPa"
+	case InchesOfMercury:
+		return "inHg"
+	case MillimetersOfMercury:
+		return "mmHg"
+	default:
+		return string(p)
+	}
+}
+
+// Symbol returns the symbol of the temperature unit, such as "°C"
+func (t Temperature) Symbol() string {
+	switch t {
+	case Celsius:
+		return "°C"
+	case Fahrenheit:
+		return "°F"
+	case Kelvin:
+		return "K"
+	default:
+		return string(t)
+	}
+}
+
+// formatQuantity formats a value followed by a unit symbol
+func formatQuantity(val float64, symbol string) string {
+	s := strconv.FormatFloat(val, 'f', -1, 64)
+	if symbol == "" {
+		return s
+	}
+	return s + " " + symbol
+}
@@ -0,0 +1,90 @@
+package units
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestQuantityIn(t *testing.T) {
+	testCases := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"length", Length{Value: 3, Unit: Miles}.In(Meters), 4828.032},
+		{"velocity", Velocity{Value: 10, Unit: Knots}.In(KilometersPerHour), 18.52},
+		{"height", HeightFeet(1000).In(HeightInMeters), 304.8},
+		{"flight levels", HeightFeet(35000).In(FlightLevels), 350},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if math.Abs(tc.got-tc.expected) > 1e-3 {
+				t.Errorf("Expected %f, got %f", tc.expected, tc.got)
+			}
+		})
+	}
+}
+
+func TestQuantityCompare(t *testing.T) {
+	vis := Length{Value: 1, Unit: Miles}
+	limit := Length{Value: 1500, Unit: Meters}
+	if vis.Less(limit) {
+		t.Errorf("Expected %s not to be less than %s", vis, limit)
+	}
+	if !limit.Less(vis) {
+		t.Errorf("Expected %s to be less than %s", limit, vis)
+	}
+	if max := limit.Max(vis); max != vis {
+		t.Errorf("Expected max to be %s, got %s", vis, max)
+	}
+
+	speed := Velocity{Value: 10, Unit: MetersPerSecond}
+	gusts := Velocity{Value: 25, Unit: Knots}
+	if max := speed.Max(gusts); max != gusts {
+		t.Errorf("Expected max to be %s, got %s", gusts, max)
+	}
+
+	low := Height{Value: 300, Unit: HeightInMeters}
+	high := HeightFeet(1000)
+	if !low.Less(high) {
+		t.Errorf("Expected %s to be less than %s", low, high)
+	}
+	if max := low.Max(high); max != high {
+		t.Errorf("Expected max to be %s, got %s", high, max)
+	}
+}
+
+func TestQuantityString(t *testing.T) {
+	testCases := []struct {
+		got      string
+		expected string
+	}{
+		{Length{Value: 1500, Unit: Meters}.String(), "1500 m"},
+		{Length{Value: 0.5, Unit: Miles}.String(), "0.5 mi"},
+		{Velocity{Value: 10, Unit: Knots}.String(), "10 kt"},
+		{Velocity{Value: 4, Unit: MetersPerSecond}.String(), "4 m/s"},
+		{HeightFeet(2000).String(), "2000 ft"},
+		{Height{Value: 350, Unit: FlightLevels}.String(), "FL350"},
+		{Height{Value: 50, Unit: FlightLevels}.String(), "FL050"},
+		{Height{Value: 12}.String(), "12"},
+	}
+
+	for _, tc := range testCases {
+		if tc.got != tc.expected {
+			t.Errorf("Expected %q, got %q", tc.expected, tc.got)
+		}
+	}
+}
+
+func TestQuantityJSON(t *testing.T) {
+	data, err := json.Marshal(Velocity{Value: 12, Unit: Knots})
+	if err != nil {
+		t.Fatalf("Error marshaling velocity: %s", err)
+	}
+
+	if expected := `{"value":12,"unit":"Knots"}`; string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
@@ -1,9 +1,77 @@
 package units
 
 import (
+	"math"
 	"strings"
 )
 
+// Rounding specifies how converted values are rounded to whole numbers.
+type Rounding int
+
+// Rounding modes
+const (
+	// Nearest rounds to the nearest whole number, with halves
+	// rounded away from zero.
+	Nearest Rounding = iota
+	// Truncate rounds towards zero.
+	Truncate
+	// Ceil rounds up, which is conservative for values
+	// such as wind speeds, where higher is worse.
+	Ceil
+	// Floor rounds down, which is conservative for values
+	// such as visibilities, where lower is worse.
+	Floor
+)
+
+// Round rounds val using the rounding mode
+func (r Rounding) Round(val float64) float64 {
+	switch r {
+	case Truncate:
+		return math.Trunc(val)
+	case Ceil:
+		return math.Ceil(val)
+	case Floor:
+		return math.Floor(val)
+	default:
+		return math.Round(val)
+	}
+}
+
+// ParseRounding parses a rounding mode. Valid inputs are
+// nearest, truncate, ceil, and floor.
+// This function is case-insensitive.
+func ParseRounding(s string) (Rounding, bool) {
+	switch strings.ToLower(s) {
+	case "nearest", "round":
+		return Nearest, true
+	case "truncate", "trunc":
+		return Truncate, true
+	case "ceil", "up":
+		return Ceil, true
+	case "floor", "down":
+		return Floor, true
+	default:
+		return 0, false
+	}
+}
+
+// convert converts a value between two units using the number of
+// base units in each unit. If either unit is unknown, or they're the
+// same unit, the value is returned unchanged.
+func convert[U comparable](factors map[U]float64, from, to U, val float64) float64 {
+	ff, ok := factors[from]
+	if !ok || from == to {
+		return val
+	}
+
+	tf, ok := factors[to]
+	if !ok {
+		return val
+	}
+
+	return val * ff / tf
+}
+
 // Speed represents a unit of speed
 type Speed string
 
@@ -15,36 +83,25 @@ const (
 	MilesPerHour      Speed = "MilesPerHour"
 )
 
-// Convert converts a value from one unit to another
-func (sf Speed) Convert(st Speed, val int) int {
-	switch {
-	case sf == MetersPerSecond && st == KilometersPerHour:
-		return int(float64(val) * 3.6)
-	case sf == KilometersPerHour && st == MetersPerSecond:
-		return int(float64(val) / 3.6)
-	case sf == Knots && st == KilometersPerHour:
-		return int(float64(val) * 1.852)
-	case sf == KilometersPerHour && st == Knots:
-		return int(float64(val) / 1.852)
-	case sf == MetersPerSecond && st == Knots:
-		return int(float64(val) * 1.94384)
-	case sf == Knots && st == MetersPerSecond:
-		return int(float64(val) / 1.94384)
-	case sf == MilesPerHour && st == KilometersPerHour:
-		return int(float64(val) * 1.60934)
-	case sf == KilometersPerHour && st == MilesPerHour:
-		return int(float64(val) / 1.60934)
-	case sf == MilesPerHour && st == MetersPerSecond:
-		return int(float64(val) * 0.44704)
-	case sf == MetersPerSecond && st == MilesPerHour:
-		return int(float64(val) / 0.44704)
-	case sf == MilesPerHour && st == Knots:
-		return int(float64(val) * 0.868976)
-	case sf == Knots && st == MilesPerHour:
-		return int(float64(val) / 0.868976)
-	default:
-		return val
-	}
+// speedFactors contains the number of meters per second in one of each speed unit
+var speedFactors = map[Speed]float64{
+	MetersPerSecond:   1,
+	KilometersPerHour: 1000.0 / 3600,
+	Knots:             1852.0 / 3600,
+	MilesPerHour:      1609.344 / 3600,
+}
+
+// Convert converts a value from one unit to another. The value is
+// converted to meters per second first, and then to the target unit.
+// If either unit is unknown, the value is returned unchanged.
+func (sf Speed) Convert(st Speed, val float64) float64 {
+	return convert(speedFactors, sf, st, val)
+}
+
+// ConvertInt converts a whole number from one unit to
+// another, rounding the result using the given mode.
+func (sf Speed) ConvertInt(st Speed, val int, r Rounding) int {
+	return int(r.Round(sf.Convert(st, float64(val))))
 }
 
 // ParseSpeed parses a speed value. Valid inputs include:
@@ -73,30 +130,26 @@ const (
 	Miles      Distance = "Miles"
 	Meters     Distance = "Meters"
 	Kilometers Distance = "Kilometers"
+	Feet       Distance = "Feet"
 )
 
-// Convert converts a value from one unit to another
+// distanceFactors contains the number of meters in one of each distance unit
+var distanceFactors = map[Distance]float64{
+	Meters:     1,
+	Kilometers: 1000,
+	Miles:      1609.344,
+	Feet:       0.3048,
+}
+
+// Convert converts a value from one unit to another. The value is
+// converted to meters first, and then to the target unit. If either
+// unit is unknown, the value is returned unchanged.
 func (df Distance) Convert(dt Distance, val float64) float64 {
-	switch {
-	case df == Miles && dt == Meters:
-		return val * 1609
-	case df == Meters && dt == Miles:
-		return val / 1609
-	case df == Kilometers && dt == Meters:
-		return val * 1000
-	case df == Meters && dt == Kilometers:
-		return val / 1000
-	case df == Miles && dt == Kilometers:
-		return val * 1.60934
-	case df == Kilometers && dt == Miles:
-		return val / 1.60934
-	default:
-		return val
-	}
+	return convert(distanceFactors, df, dt, val)
 }
 
 // ParseDistance parses a distance value. Valid inputs include:
-// sm, mi, m, km, and kilometers.
+// sm, mi, m, km, ft, and kilometers.
 // This function is case-insensitive.
 func ParseDistance(s string) (Distance, bool) {
 	switch strings.ToLower(s) {
@@ -106,6 +159,176 @@ func ParseDistance(s string) (Distance, bool) {
 		return Meters, true
 	case "km", "kilometer", "kilometers", "kilometre", "kilometres":
 		return Kilometers, true
+	case "ft", "foot", "feet":
+		return Feet, true
+	default:
+		return "", false
+	}
+}
+
+// HeightUnit represents a unit of height, such as the height of a cloud
+// base. It's separate from Distance, so that heights can be given in
+// flight levels without allowing visibilities in flight levels. The
+// name Height is taken by the type of height values, which pair a value
+// with a HeightUnit.
+type HeightUnit string
+
+// Height units
+const (
+	HeightInFeet   HeightUnit = "Feet"
+	HeightInMeters HeightUnit = "Meters"
+	// FlightLevels are hundreds of feet
+	FlightLevels HeightUnit = "FlightLevels"
+)
+
+// heightFactors contains the number of meters in one of each height unit
+var heightFactors = map[HeightUnit]float64{
+	HeightInMeters: 1,
+	HeightInFeet:   0.3048,
+	FlightLevels:   30.48,
+}
+
+// Convert converts a value from one unit to another. The value is
+// converted to meters first, and then to the target unit. If either
+// unit is unknown, the value is returned unchanged.
+func (hf HeightUnit) Convert(ht HeightUnit, val float64) float64 {
+	return convert(heightFactors, hf, ht, val)
+}
+
+// ParseHeight parses a unit used for heights, such as cloud bases.
+// Valid inputs include: ft, m, fl, and flightlevels.
+// This function is case-insensitive.
+func ParseHeight(s string) (HeightUnit, bool) {
+	switch strings.ToLower(s) {
+	case "ft", "foot", "feet":
+		return HeightInFeet, true
+	case "m", "meter", "meters", "metre", "metres":
+		return HeightInMeters, true
+	case "fl", "flightlevel", "flightlevels", "flight level", "flight levels":
+		return FlightLevels, true
+	default:
+		return "", false
+	}
+}
+
+// Pressure represents a unit of pressure
+type Pressure string
+
+// Pressure units
+const (
+	Hectopascals         Pressure = "Hectopascals"
+	InchesOfMercury      Pressure = "InchesOfMercury"
+	MillimetersOfMercury Pressure = "MillimetersOfMercury"
+)
+
+// pressureFactors contains the number of hectopascals in one of each pressure unit
+var pressureFactors = map[Pressure]float64{
+	Hectopascals:         1,
+	InchesOfMercury:      33.8638866667,
+	MillimetersOfMercury: 1.33322387415,
+}
+
+// Convert converts a value from one unit to another. The value is
+// converted to hectopascals first, and then to the target unit. If
+// either unit is unknown, the value is returned unchanged.
+func (pf Pressure) Convert(pt Pressure, val float64) float64 {
+	return convert(pressureFactors, pf, pt, val)
+}
+
+// ParsePressure parses a pressure value. Valid inputs include:
+// hpa, mb, inhg, ins, and mmhg.
+// This function is case-insensitive.
+func ParsePressure(s string) (Pressure, bool) {
+	switch strings.ToLower(s) {
+	case "hpa", "hectopascal", "hectopascals", "mb", "mbar", "millibar", "millibars":
+		return Hectopascals, true
+	case "inhg", "ins", "inchesofmercury", "inches of mercury":
+		return InchesOfMercury, true
+	case "mmhg", "torr", "millimetersofmercury", "millimeters of mercury", "millimetresofmercury", "millimetres of mercury":
+		return MillimetersOfMercury, true
+	default:
+		return "", false
+	}
+}
+
+// Temperature represents a unit of temperature
+type Temperature string
+
+// Temperature units
+const (
+	Celsius    Temperature = "Celsius"
+	Fahrenheit Temperature = "Fahrenheit"
+	Kelvin     Temperature = "Kelvin"
+)
+
+// toKelvin converts a temperature in the given unit to kelvins.
+// If the unit is unknown, ok is false.
+func (t Temperature) toKelvin(val float64) (k float64, ok bool) {
+	switch t {
+	case Celsius:
+		return val + 273.15, true
+	case Fahrenheit:
+		return (val-32)*5/9 + 273.15, true
+	case Kelvin:
+		return val, true
+	default:
+		return 0, false
+	}
+}
+
+// fromKelvin converts a temperature in kelvins to the given unit.
+// If the unit is unknown, ok is false.
+func (t Temperature) fromKelvin(k float64) (val float64, ok bool) {
+	switch t {
+	case Celsius:
+		return k - 273.15, true
+	case Fahrenheit:
+		return (k-273.15)*9/5 + 32, true
+	case Kelvin:
+		return k, true
+	default:
+		return 0, false
+	}
+}
+
+// Convert converts a value from one unit to another. Temperature scales
+// have different zero points, so the value is converted to kelvins first,
+// and then to the target unit. If either unit is unknown, the value is
+// returned unchanged.
+func (tf Temperature) Convert(tt Temperature, val float64) float64 {
+	if tf == tt {
+		return val
+	}
+
+	k, ok := tf.toKelvin(val)
+	if !ok {
+		return val
+	}
+
+	out, ok := tt.fromKelvin(k)
+	if !ok {
+		return val
+	}
+	return out
+}
+
+// ConvertInt converts a whole number from one unit to
+// another, rounding the result using the given mode.
+func (tf Temperature) ConvertInt(tt Temperature, val int, r Rounding) int {
+	return int(r.Round(tf.Convert(tt, float64(val))))
+}
+
+// ParseTemperature parses a temperature value. Valid inputs include:
+// c, f, k, celsius, and fahrenheit.
+// This function is case-insensitive.
+func ParseTemperature(s string) (Temperature, bool) {
+	switch strings.ToLower(s) {
+	case "c", "°c", "degc", "celsius":
+		return Celsius, true
+	case "f", "°f", "degf", "fahrenheit":
+		return Fahrenheit, true
+	case "k", "kelvin", "kelvins":
+		return Kelvin, true
 	default:
 		return "", false
 	}
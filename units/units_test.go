@@ -0,0 +1,145 @@
+package units
+
+import (
+	"math"
+	"testing"
+	"testing/quick"
+)
+
+// roundTrips checks that converting a value to another unit and
+// back gives the original value, for every pair of units.
+func roundTrips[U comparable](t *testing.T, all []U, conv func(from, to U, val float64) float64) {
+	for _, from := range all {
+		for _, to := range all {
+			f := func(val float64) bool {
+				// Keep values within a range where the relative error is meaningful
+				if math.IsNaN(val) || math.IsInf(val, 0) || math.Abs(val) > 1e12 {
+					return true
+				}
+
+				back := conv(to, from, conv(from, to, val))
+				return math.Abs(back-val) <= 1e-12*math.Max(1, math.Abs(val))
+			}
+
+			if err := quick.Check(f, nil); err != nil {
+				t.Errorf("%v -> %v: %s", from, to, err)
+			}
+		}
+	}
+}
+
+// unitsOf returns the units in a conversion factor map
+func unitsOf[U comparable](factors map[U]float64) []U {
+	out := make([]U, 0, len(factors))
+	for unit := range factors {
+		out = append(out, unit)
+	}
+	return out
+}
+
+func TestRoundTrip(t *testing.T) {
+	roundTrips(t, unitsOf(speedFactors), Speed.Convert)
+	roundTrips(t, unitsOf(distanceFactors), Distance.Convert)
+	roundTrips(t, unitsOf(heightFactors), HeightUnit.Convert)
+	roundTrips(t, unitsOf(pressureFactors), Pressure.Convert)
+	// Temperatures are converted with an offset as well as a factor,
+	// so they don't have a factor map.
+	roundTrips(t, []Temperature{Celsius, Fahrenheit, Kelvin}, Temperature.Convert)
+}
+
+func TestConvert(t *testing.T) {
+	testCases := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"mps to kph", MetersPerSecond.Convert(KilometersPerHour, 4), 14.4},
+		{"kts to kph", Knots.Convert(KilometersPerHour, 10), 18.52},
+		{"mph to kts", MilesPerHour.Convert(Knots, 100), 86.897624},
+		{"mi to m", Miles.Convert(Meters, 1), 1609.344},
+		{"mi to km", Miles.Convert(Kilometers, 1), 1.609344},
+		{"ft to mi", Feet.Convert(Miles, 5280), 1},
+		{"inhg to hpa", InchesOfMercury.Convert(Hectopascals, 29.92), 1013.207},
+		{"unknown unit", Speed("").Convert(Knots, 5), 5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if math.Abs(tc.got-tc.expected) > 1e-3 {
+				t.Errorf("Expected %f, got %f", tc.expected, tc.got)
+			}
+		})
+	}
+}
+
+func TestConvertInt(t *testing.T) {
+	testCases := []struct {
+		rounding Rounding
+		val      int
+		expected int
+	}{
+		{Nearest, 4, 14},
+		{Nearest, 5, 18},
+		{Truncate, 5, 18},
+		{Ceil, 4, 15},
+		{Floor, 4, 14},
+	}
+
+	for _, tc := range testCases {
+		got := MetersPerSecond.ConvertInt(KilometersPerHour, tc.val, tc.rounding)
+		if got != tc.expected {
+			t.Errorf("%d MPS with rounding %d: expected %d, got %d", tc.val, tc.rounding, tc.expected, got)
+		}
+	}
+}
+
+func TestConvertTemperature(t *testing.T) {
+	testCases := []struct {
+		name     string
+		got      float64
+		expected float64
+	}{
+		{"c to f", Celsius.Convert(Fahrenheit, 25), 77},
+		{"f to c", Fahrenheit.Convert(Celsius, -40), -40},
+		{"c to k", Celsius.Convert(Kelvin, -2), 271.15},
+		{"k to f", Kelvin.Convert(Fahrenheit, 273.15), 32},
+		{"unknown unit", Temperature("").Convert(Celsius, 5), 5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if math.Abs(tc.got-tc.expected) > 1e-9 {
+				t.Errorf("Expected %f, got %f", tc.expected, tc.got)
+			}
+		})
+	}
+
+	if got := Celsius.ConvertInt(Fahrenheit, -2, Nearest); got != 28 {
+		t.Errorf("Expected -2°C to be 28°F, got %d", got)
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	if u, ok := ParseTemperature("F"); !ok || u != Fahrenheit {
+		t.Errorf("Expected Fahrenheit, got %q", u)
+	}
+	if u, ok := ParsePressure("mmHg"); !ok || u != MillimetersOfMercury {
+		t.Errorf("Expected MillimetersOfMercury, got %q", u)
+	}
+	if u, ok := ParseHeight("FL"); !ok || u != FlightLevels {
+		t.Errorf("Expected FlightLevels, got %q", u)
+	}
+	if _, ok := ParseHeight("km"); ok {
+		t.Error("Expected kilometers not to be a valid height unit")
+	}
+	if _, ok := ParseDistance("fl"); ok {
+		t.Error("Expected flight levels not to be a valid distance unit")
+	}
+
+	if got := HeightInFeet.Convert(FlightLevels, 35000); math.Abs(got-350) > 1e-9 {
+		t.Errorf("Expected 35000 ft to be FL350, got %f", got)
+	}
+	if got := Hectopascals.Convert(MillimetersOfMercury, 1013.25); math.Abs(got-760) > 1e-3 {
+		t.Errorf("Expected 1013.25 hPa to be 760 mmHg, got %f", got)
+	}
+}
@@ -0,0 +1,282 @@
+package taf
+
+import (
+	"slices"
+	"strings"
+
+	"go.elara.ws/taf/units"
+)
+
+// UnitSystem describes the units a forecast can be converted to.
+// If a unit is empty, values of that kind are left unchanged.
+type UnitSystem struct {
+	// Name is the name of the unit system.
+	Name string
+
+	// Speed is the unit of wind speeds.
+	Speed units.Speed
+
+	// Distance is the unit of visibilities.
+	Distance units.Distance
+
+	// Temperature is the unit of temperatures.
+	Temperature units.Temperature
+
+	// Pressure is the unit of altimeter settings.
+	Pressure units.Pressure
+
+	// Height is the unit of heights, such as cloud bases
+	// and wind shear heights.
+	Height units.HeightUnit
+
+	// Rounding specifies how converted values that are stored as
+	// whole numbers, such as wind speeds and temperatures, are rounded.
+	Rounding units.Rounding
+}
+
+// Metric uses kilometers per hour, meters, degrees Celsius,
+// hectopascals, and meters for heights.
+var Metric = UnitSystem{
+	Name:        "metric",
+	Speed:       units.KilometersPerHour,
+	Distance:    units.Meters,
+	Temperature: units.Celsius,
+	Pressure:    units.Hectopascals,
+	Height:      units.HeightInMeters,
+}
+
+// ICAO uses the units most commonly used in aviation around the
+// world: knots, meters, degrees Celsius, hectopascals, and feet.
+var ICAO = UnitSystem{
+	Name:        "icao",
+	Speed:       units.Knots,
+	Distance:    units.Meters,
+	Temperature: units.Celsius,
+	Pressure:    units.Hectopascals,
+	Height:      units.HeightInFeet,
+}
+
+// US uses the units used in US reports: knots, statute miles,
+// degrees Fahrenheit, inches of mercury, and feet.
+var US = UnitSystem{
+	Name:        "us",
+	Speed:       units.Knots,
+	Distance:    units.Miles,
+	Temperature: units.Fahrenheit,
+	Pressure:    units.InchesOfMercury,
+	Height:      units.HeightInFeet,
+}
+
+// SI uses meters per second, meters, kelvins, hectopascals,
+// and meters for heights.
+var SI = UnitSystem{
+	Name:        "si",
+	Speed:       units.MetersPerSecond,
+	Distance:    units.Meters,
+	Temperature: units.Kelvin,
+	Pressure:    units.Hectopascals,
+	Height:      units.HeightInMeters,
+}
+
+// ParseUnitSystem returns the unit system with the given name.
+// Valid names are metric, icao, us, and si.
+// This function is case-insensitive.
+func ParseUnitSystem(name string) (UnitSystem, bool) {
+	switch strings.ToLower(name) {
+	case Metric.Name:
+		return Metric, true
+	case ICAO.Name:
+		return ICAO, true
+	case US.Name:
+		return US, true
+	case SI.Name:
+		return SI, true
+	default:
+		return UnitSystem{}, false
+	}
+}
+
+// unitSystem returns the unit system described by the options
+func (o Options) unitSystem() UnitSystem {
+	return UnitSystem{
+		Speed:       o.SpeedUnit,
+		Distance:    o.DistanceUnit,
+		Temperature: o.TemperatureUnit,
+		Pressure:    o.PressureUnit,
+		Height:      o.HeightUnit,
+		Rounding:    o.Rounding,
+	}
+}
+
+// ConvertUnits returns a copy of the forecast with the values in the base
+// forecast and every change and probability group converted to the given
+// unit system. The forecast itself isn't modified.
+func (fc *Forecast) ConvertUnits(us UnitSystem) *Forecast {
+	out := *fc
+	out.Visibility = us.convertVisibility(fc.Visibility)
+	out.Wind = us.convertWind(fc.Wind)
+	out.WindShear = us.convertWind(fc.WindShear)
+	out.SkyCondition = us.convertSkyConditions(fc.SkyCondition)
+	out.Temperature = us.convertTemperatures(fc.Temperature)
+	out.Altimeter = us.convertAltimeter(fc.Altimeter)
+	out.Weather = slices.Clone(fc.Weather)
+	out.Icing = us.convertIcing(fc.Icing)
+	out.Turbulence = us.convertTurbulence(fc.Turbulence)
+	out.Flags = slices.Clone(fc.Flags)
+	out.Warnings = slices.Clone(fc.Warnings)
+	out.Unparsed = slices.Clone(fc.Unparsed)
+	out.Spans = slices.Clone(fc.Spans)
+
+	if fc.Changes != nil {
+		out.Changes = make([]*Change, len(fc.Changes))
+		for i, ch := range fc.Changes {
+			out.Changes[i] = ch.convertUnits(us)
+		}
+	}
+
+	if fc.Probabilities != nil {
+		out.Probabilities = make([]*Probability, len(fc.Probabilities))
+		for i, pr := range fc.Probabilities {
+			out.Probabilities[i] = pr.convertUnits(us)
+		}
+	}
+
+	return &out
+}
+
+// convertUnits returns a copy of the change converted to the given unit system
+func (ch *Change) convertUnits(us UnitSystem) *Change {
+	out := *ch
+	out.Visibility = us.convertVisibility(ch.Visibility)
+	out.Wind = us.convertWind(ch.Wind)
+	out.WindShear = us.convertWind(ch.WindShear)
+	out.SkyCondition = us.convertSkyConditions(ch.SkyCondition)
+	out.Temperature = us.convertTemperatures(ch.Temperature)
+	out.Altimeter = us.convertAltimeter(ch.Altimeter)
+	out.Weather = slices.Clone(ch.Weather)
+	out.Icing = us.convertIcing(ch.Icing)
+	out.Turbulence = us.convertTurbulence(ch.Turbulence)
+	out.Flags = slices.Clone(ch.Flags)
+	out.Spans = slices.Clone(ch.Spans)
+	return &out
+}
+
+// convertUnits returns a copy of the probability group
+// converted to the given unit system
+func (pr *Probability) convertUnits(us UnitSystem) *Probability {
+	out := *pr
+	out.Visibility = us.convertVisibility(pr.Visibility)
+	out.Wind = us.convertWind(pr.Wind)
+	out.WindShear = us.convertWind(pr.WindShear)
+	out.SkyCondition = us.convertSkyConditions(pr.SkyCondition)
+	out.Temperature = us.convertTemperatures(pr.Temperature)
+	out.Altimeter = us.convertAltimeter(pr.Altimeter)
+	out.Weather = slices.Clone(pr.Weather)
+	out.Icing = us.convertIcing(pr.Icing)
+	out.Turbulence = us.convertTurbulence(pr.Turbulence)
+	out.Flags = slices.Clone(pr.Flags)
+	out.Spans = slices.Clone(pr.Spans)
+	return &out
+}
+
+// convertVisibility converts a visibility to the system's distance unit
+func (us UnitSystem) convertVisibility(v Visibility) Visibility {
+	if us.Distance == "" || v.Unit == "" {
+		return v
+	}
+	v.Length = units.Length{Value: v.In(us.Distance), Unit: us.Distance}
+	return v
+}
+
+// convertWind converts the speeds of a wind to the system's speed
+// unit and its wind shear height to the system's height unit
+func (us UnitSystem) convertWind(w Wind) Wind {
+	w.Speed = us.convertVelocity(w.Speed)
+	w.Gusts = us.convertVelocity(w.Gusts)
+	w.WindShear = us.convertHeight(w.WindShear)
+	return w
+}
+
+// convertVelocity converts a speed to the system's speed unit,
+// rounding it to a whole number
+func (us UnitSystem) convertVelocity(v units.Velocity) units.Velocity {
+	if us.Speed == "" || v.Unit == "" {
+		return v
+	}
+	return units.Velocity{Value: us.Rounding.Round(v.In(us.Speed)), Unit: us.Speed}
+}
+
+// convertHeight converts a height to the system's height unit
+func (us UnitSystem) convertHeight(h units.Height) units.Height {
+	if us.Height == "" || h.Unit == "" {
+		return h
+	}
+	return units.Height{Value: h.In(us.Height), Unit: us.Height}
+}
+
+// convertSkyConditions returns a copy of the sky conditions
+// with their altitudes converted to the system's height unit
+func (us UnitSystem) convertSkyConditions(sky []SkyCondition) []SkyCondition {
+	out := slices.Clone(sky)
+	for i := range out {
+		out[i].Altitude = us.convertHeight(out[i].Altitude)
+	}
+	return out
+}
+
+// convertIcing returns a copy of the icing layers with their
+// heights converted to the system's height unit
+func (us UnitSystem) convertIcing(layers []Icing) []Icing {
+	out := slices.Clone(layers)
+	for i := range out {
+		out[i].Base = us.convertHeight(out[i].Base)
+		out[i].Thickness = us.convertHeight(out[i].Thickness)
+	}
+	return out
+}
+
+// convertTurbulence returns a copy of the turbulence layers with
+// their heights converted to the system's height unit
+func (us UnitSystem) convertTurbulence(layers []Turbulence) []Turbulence {
+	out := slices.Clone(layers)
+	for i := range out {
+		out[i].Base = us.convertHeight(out[i].Base)
+		out[i].Thickness = us.convertHeight(out[i].Thickness)
+	}
+	return out
+}
+
+// convertTemperature converts a temperature to the system's
+// temperature unit, rounding it to a whole number
+func (us UnitSystem) convertTemperature(t Temperature) Temperature {
+	from := t.Unit
+	if from == "" {
+		from = units.Celsius
+	}
+
+	if us.Temperature == "" || from == us.Temperature {
+		return t
+	}
+
+	t.Value = from.ConvertInt(us.Temperature, t.Value, us.Rounding)
+	t.Unit = us.Temperature
+	return t
+}
+
+// convertTemperatures returns a copy of the temperatures
+// converted to the system's temperature unit
+func (us UnitSystem) convertTemperatures(temps []Temperature) []Temperature {
+	out := slices.Clone(temps)
+	for i := range out {
+		out[i] = us.convertTemperature(out[i])
+	}
+	return out
+}
+
+// convertAltimeter converts an altimeter setting to the system's pressure unit
+func (us UnitSystem) convertAltimeter(alt Altimeter) Altimeter {
+	if us.Pressure == "" || alt.Unit == "" {
+		return alt
+	}
+	return Altimeter{Value: alt.Unit.Convert(us.Pressure, alt.Value), Unit: us.Pressure}
+}
@@ -0,0 +1,92 @@
+package taf
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+	"go.elara.ws/taf/units"
+)
+
+const unitSystemData = `TAF EGLL 211700Z 2118/2224 24012G25KT 9999 BKN040 QNH2992INS TX25/2214Z TNM02/2205Z
+  TEMPO 2118/2122 4000 SHRA BKN012
+  PROB30 2200/2204 0800 FG VV002`
+
+func TestConvertUnits(t *testing.T) {
+	opts := Options{Month: time.August, Year: 2023}
+	fc, err := DecodeWithOptions(strings.NewReader(unitSystemData), opts)
+	if err != nil {
+		t.Fatalf("Error during parsing: %s", err)
+	}
+	orig := fc.String()
+
+	out := fc.ConvertUnits(US)
+
+	if fc.String() != orig || fc.Visibility.Unit != units.Meters {
+		t.Error("Expected the original forecast not to be modified")
+	}
+
+	if out.Wind.Speed.Unit != units.Knots || out.Wind.Gusts.Value != 25 {
+		t.Errorf("Expected wind to stay in knots, got %v", out.Wind)
+	}
+
+	if v := out.Visibility; v.Unit != units.Miles || math.Abs(v.Value-6.2131) > 1e-3 {
+		t.Errorf("Expected visibility of 6.2131 mi, got %s", v.Length)
+	}
+
+	if tx := out.Temperature[0]; tx.Value != 77 || tx.Unit != units.Fahrenheit {
+		t.Errorf("Expected maximum temperature of 77°F, got %v", tx)
+	}
+
+	if vis := out.Changes[0].Visibility; vis.Unit != units.Miles {
+		t.Errorf("Expected change visibility in miles, got %s", vis.Length)
+	}
+
+	if alt := out.Probabilities[0].SkyCondition[0].Altitude; alt != units.HeightFeet(200) {
+		t.Errorf("Expected probability ceiling of 200 ft, got %s", alt)
+	}
+
+	if out.Changes[0] == fc.Changes[0] || &out.SkyCondition[0] == &fc.SkyCondition[0] {
+		t.Error("Expected groups to be copied")
+	}
+}
+
+// TestConvertUnitsMatchesOptions checks that converting a forecast after
+// decoding it gives the same result as converting it while decoding.
+func TestConvertUnitsMatchesOptions(t *testing.T) {
+	for _, us := range []UnitSystem{Metric, ICAO, US, SI} {
+		t.Run(us.Name, func(t *testing.T) {
+			opts := Options{Month: time.August, Year: 2023}
+			fc, err := DecodeWithOptions(strings.NewReader(unitSystemData), opts)
+			if err != nil {
+				t.Fatalf("Error during parsing: %s", err)
+			}
+
+			opts.SpeedUnit = us.Speed
+			opts.DistanceUnit = us.Distance
+			opts.TemperatureUnit = us.Temperature
+			opts.PressureUnit = us.Pressure
+			opts.HeightUnit = us.Height
+			expected, err := DecodeWithOptions(strings.NewReader(unitSystemData), opts)
+			if err != nil {
+				t.Fatalf("Error during parsing: %s", err)
+			}
+
+			if diff := deep.Equal(fc.ConvertUnits(us), expected); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestParseUnitSystem(t *testing.T) {
+	if us, ok := ParseUnitSystem("ICAO"); !ok || us.Name != ICAO.Name {
+		t.Errorf("Expected the ICAO unit system, got %q", us.Name)
+	}
+
+	if _, ok := ParseUnitSystem("imperial"); ok {
+		t.Error("Expected unknown unit system to be rejected")
+	}
+}
@@ -0,0 +1,301 @@
+package taf
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.elara.ws/taf/units"
+)
+
+// Severity represents how serious a rule violation is.
+type Severity string
+
+// Severities
+const (
+	// SeverityError indicates that a forecast doesn't conform to the ruleset.
+	SeverityError Severity = "error"
+	// SeverityWarning indicates that a forecast conforms to the ruleset,
+	// but goes against its recommended practices.
+	SeverityWarning Severity = "warning"
+)
+
+// Rule IDs
+const (
+	// RuleValidity checks the length of the forecast's validity period.
+	RuleValidity = "validity"
+	// RuleChangeValidity checks that change and probability groups are
+	// within the forecast's validity period.
+	RuleChangeValidity = "change-validity"
+	// RuleChangeOrder checks that change groups are in chronological order.
+	RuleChangeOrder = "change-order"
+	// RuleFromOverlap checks that FM groups don't overlap.
+	RuleFromOverlap = "fm-overlap"
+	// RuleBecomingDuration checks the length of BECMG periods.
+	RuleBecomingDuration = "becmg-duration"
+	// RuleProbabilityValue checks the values of PROB groups.
+	RuleProbabilityValue = "prob-value"
+	// RuleProbabilityLead checks that PROB groups don't start too early.
+	RuleProbabilityLead = "prob-lead"
+	// RuleTemperatureTime checks that TX and TN times are within the
+	// forecast's validity period.
+	RuleTemperatureTime = "temp-time"
+	// RuleVisibilityValue checks that visibilities are reportable values.
+	RuleVisibilityValue = "visibility-value"
+)
+
+// Violation describes a part of a forecast that doesn't conform to a ruleset.
+type Violation struct {
+	// Rule is the ID of the rule that was violated.
+	Rule string `json:"rule"`
+
+	// Severity indicates how serious the violation is.
+	Severity Severity `json:"severity"`
+
+	// Location identifies the group the violation was found in
+	// (e.g. "changes[1]"). It's empty for the main forecast.
+	Location string `json:"location,omitempty"`
+
+	// Message describes the violation.
+	Message string `json:"message"`
+}
+
+// String returns a string describing the violation
+func (v Violation) String() string {
+	if v.Location == "" {
+		return fmt.Sprintf("%s: %s: %s", v.Severity, v.Rule, v.Message)
+	}
+	return fmt.Sprintf("%s: %s: %s: %s", v.Severity, v.Rule, v.Location, v.Message)
+}
+
+// Ruleset describes the requirements a forecast has to meet.
+type Ruleset struct {
+	// Name is the name of the ruleset.
+	Name string
+
+	// MaxValidity is the maximum length of the forecast's validity period.
+	MaxValidity time.Duration
+
+	// NormalBecomingDuration is the length of BECMG periods above
+	// which a warning is reported. If it's zero, it isn't checked.
+	NormalBecomingDuration time.Duration
+
+	// MaxBecomingDuration is the maximum length of BECMG periods.
+	MaxBecomingDuration time.Duration
+
+	// ProbabilityValues contains the allowed values of PROB groups.
+	ProbabilityValues []int
+
+	// MinProbabilityLead is the minimum time between the start of the
+	// forecast and the start of a PROB group. If it's zero, it isn't checked.
+	MinProbabilityLead time.Duration
+
+	// VisibilityValues contains the reportable visibility values, in
+	// ascending order. Their unit is determined by VisibilityUnit.
+	VisibilityValues []float64
+
+	// VisibilityUnit is the unit of VisibilityValues.
+	VisibilityUnit units.Distance
+}
+
+// Annex3 contains the rules from ICAO Annex 3
+var Annex3 = Ruleset{
+	Name:                   "annex3",
+	MaxValidity:            30 * time.Hour,
+	NormalBecomingDuration: 2 * time.Hour,
+	MaxBecomingDuration:    4 * time.Hour,
+	ProbabilityValues:      []int{30, 40},
+	VisibilityValues:       append(visibilitySteps(10000, 50, 100, 1000), 9999),
+	VisibilityUnit:         units.Meters,
+}
+
+// FMH1 contains the rules from the US Federal Meteorological Handbook No. 1
+// and the NWS directives for TAFs.
+var FMH1 = Ruleset{
+	Name:                   "fmh1",
+	MaxValidity:            30 * time.Hour,
+	NormalBecomingDuration: 2 * time.Hour,
+	MaxBecomingDuration:    4 * time.Hour,
+	ProbabilityValues:      []int{30},
+	MinProbabilityLead:     9 * time.Hour,
+	VisibilityValues:       []float64{0, 0.25, 0.5, 0.75, 1, 1.5, 2, 3, 4, 5, 6},
+	VisibilityUnit:         units.Miles,
+}
+
+// ParseRuleset returns the ruleset with the given name.
+// Valid names are annex3 and fmh1.
+func ParseRuleset(name string) (Ruleset, bool) {
+	switch name {
+	case Annex3.Name:
+		return Annex3, true
+	case FMH1.Name:
+		return FMH1, true
+	default:
+		return Ruleset{}, false
+	}
+}
+
+// Validate checks that fc conforms to the given ruleset and returns
+// every violation that was found. Forecasts that are missing or
+// cancelled have no content to validate.
+func Validate(fc *Forecast, ruleset Ruleset) []Violation {
+	if slices.Contains(fc.Flags, Missing) || slices.Contains(fc.Flags, Cancelled) {
+		return nil
+	}
+
+	v := &validator{fc: fc, rs: ruleset}
+
+	if fc.Valid.To.Sub(fc.Valid.From) > ruleset.MaxValidity {
+		v.add(RuleValidity, SeverityError, "", "validity period is longer than %s", formatDuration(ruleset.MaxValidity))
+	}
+
+	v.temperatures("", fc.Temperature)
+	v.visibility("", fc.Visibility)
+
+	var lastStart, lastFrom time.Time
+	for i, ch := range fc.Changes {
+		loc := "changes[" + strconv.Itoa(i) + "]"
+
+		v.period(loc, ch.Valid)
+		v.temperatures(loc, ch.Temperature)
+		v.visibility(loc, ch.Visibility)
+
+		if ch.Valid.From.Before(lastStart) {
+			v.add(RuleChangeOrder, SeverityWarning, loc, "change starts before the previous change")
+		}
+		lastStart = ch.Valid.From
+
+		switch ch.Type {
+		case From:
+			if !lastFrom.IsZero() && !ch.Valid.From.After(lastFrom) {
+				v.add(RuleFromOverlap, SeverityError, loc, "FM group doesn't start after the previous FM group")
+			}
+			lastFrom = ch.Valid.From
+		case Becoming:
+			v.becoming(loc, ch.Valid)
+		}
+
+		if ch.Probability != 0 {
+			v.probability(loc, ch.Probability, ch.Valid)
+		}
+	}
+
+	for i, prob := range fc.Probabilities {
+		loc := "probabilities[" + strconv.Itoa(i) + "]"
+
+		v.period(loc, prob.Valid)
+		v.temperatures(loc, prob.Temperature)
+		v.visibility(loc, prob.Visibility)
+		v.probability(loc, prob.Value, prob.Valid)
+	}
+
+	return v.out
+}
+
+// validator collects the violations found in a forecast
+type validator struct {
+	fc  *Forecast
+	rs  Ruleset
+	out []Violation
+}
+
+// add adds a violation to the output
+func (v *validator) add(rule string, severity Severity, loc, format string, args ...any) {
+	v.out = append(v.out, Violation{
+		Rule:     rule,
+		Severity: severity,
+		Location: loc,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// period checks that a change period is within the forecast's validity period
+func (v *validator) period(loc string, vp ValidPair) {
+	if vp.From.Before(v.fc.Valid.From) || (!v.fc.Valid.To.IsZero() && vp.From.After(v.fc.Valid.To)) {
+		v.add(RuleChangeValidity, SeverityError, loc, "starts outside the validity period")
+	}
+
+	if !vp.To.IsZero() && !v.fc.Valid.To.IsZero() && vp.To.After(v.fc.Valid.To) {
+		v.add(RuleChangeValidity, SeverityError, loc, "ends after the validity period")
+	}
+}
+
+// becoming checks the length of a BECMG period
+func (v *validator) becoming(loc string, vp ValidPair) {
+	duration := vp.To.Sub(vp.From)
+	switch {
+	case v.rs.MaxBecomingDuration != 0 && duration > v.rs.MaxBecomingDuration:
+		v.add(RuleBecomingDuration, SeverityError, loc, "BECMG period is longer than %s", formatDuration(v.rs.MaxBecomingDuration))
+	case v.rs.NormalBecomingDuration != 0 && duration > v.rs.NormalBecomingDuration:
+		v.add(RuleBecomingDuration, SeverityWarning, loc, "BECMG period is longer than %s", formatDuration(v.rs.NormalBecomingDuration))
+	}
+}
+
+// probability checks the value and start time of a PROB group
+func (v *validator) probability(loc string, value int, vp ValidPair) {
+	if !slices.Contains(v.rs.ProbabilityValues, value) {
+		v.add(RuleProbabilityValue, SeverityError, loc, "PROB%d isn't allowed", value)
+	}
+
+	if v.rs.MinProbabilityLead != 0 && vp.From.Sub(v.fc.Valid.From) < v.rs.MinProbabilityLead {
+		v.add(RuleProbabilityLead, SeverityError, loc, "PROB group starts within %s of the validity period", formatDuration(v.rs.MinProbabilityLead))
+	}
+}
+
+// temperatures checks that the times of the given
+// temperatures are within the forecast's validity period
+func (v *validator) temperatures(loc string, temps []Temperature) {
+	for _, temp := range temps {
+		if temp.Time.Before(v.fc.Valid.From) || (!v.fc.Valid.To.IsZero() && temp.Time.After(v.fc.Valid.To)) {
+			v.add(RuleTemperatureTime, SeverityError, loc, "%s temperature time is outside the validity period", temp.Type)
+		}
+	}
+}
+
+// visibility checks that vis is a reportable value
+func (v *validator) visibility(loc string, vis Visibility) {
+	if vis.Unit == "" || len(v.rs.VisibilityValues) == 0 {
+		return
+	}
+
+	val := vis.In(v.rs.VisibilityUnit)
+	for _, reportable := range v.rs.VisibilityValues {
+		// Allow for rounding errors caused by unit conversions
+		if math.Abs(val-reportable) <= math.Max(reportable, 1)*0.005 {
+			return
+		}
+	}
+
+	val = math.Round(val*100) / 100
+	v.add(RuleVisibilityValue, SeverityError, loc, "%s %s isn't a reportable visibility", formatFloat(val), v.rs.VisibilityUnit)
+}
+
+// visibilitySteps returns the reportable visibilities in meters below
+// max, using steps of small below 800m, medium below 5km, and large above.
+func visibilitySteps(max, small, medium, large float64) []float64 {
+	var out []float64
+	for val := 0.0; val < max; {
+		out = append(out, val)
+		switch {
+		case val < 800:
+			val += small
+		case val < 5000:
+			val += medium
+		default:
+			val += large
+		}
+	}
+	return out
+}
+
+// formatDuration formats a duration in hours, such as "4h"
+func formatDuration(d time.Duration) string {
+	return formatFloat(d.Hours()) + "h"
+}
+
+// formatFloat formats a float without any unnecessary decimal places
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
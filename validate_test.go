@@ -0,0 +1,113 @@
+package taf
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name     string
+		data     string
+		ruleset  Ruleset
+		expected []Violation
+	}{
+		{
+			name: "valid",
+			data: `TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040 TX25/2214Z TN14/2205Z
+  BECMG 2201/2203 BKN007
+  PROB30 TEMPO 2202/2206 0800 BKN004
+  FM221200 24010KT CAVOK`,
+			ruleset: Annex3,
+		},
+		{
+			name: "change outside validity",
+			data: `TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040
+  TEMPO 2220/2302 4000 BKN004`,
+			ruleset: Annex3,
+			expected: []Violation{
+				{Rule: RuleChangeValidity, Severity: SeverityError, Location: "changes[0]", Message: "ends after the validity period"},
+			},
+		},
+		{
+			name: "overlapping FM groups",
+			data: `TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040
+  FM221200 24010KT CAVOK
+  FM220600 24010KT 9000 BKN020`,
+			ruleset: Annex3,
+			expected: []Violation{
+				{Rule: RuleChangeOrder, Severity: SeverityWarning, Location: "changes[1]", Message: "change starts before the previous change"},
+				{Rule: RuleFromOverlap, Severity: SeverityError, Location: "changes[1]", Message: "FM group doesn't start after the previous FM group"},
+			},
+		},
+		{
+			name: "long BECMG",
+			data: `TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040
+  BECMG 2200/2203 BKN020
+  BECMG 2206/2212 BKN010`,
+			ruleset: Annex3,
+			expected: []Violation{
+				{Rule: RuleBecomingDuration, Severity: SeverityWarning, Location: "changes[0]", Message: "BECMG period is longer than 2h"},
+				{Rule: RuleBecomingDuration, Severity: SeverityError, Location: "changes[1]", Message: "BECMG period is longer than 4h"},
+			},
+		},
+		{
+			name: "probability",
+			data: `TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040
+  PROB50 2202/2206 0800 FG`,
+			ruleset: Annex3,
+			expected: []Violation{
+				{Rule: RuleProbabilityValue, Severity: SeverityError, Location: "probabilities[0]", Message: "PROB50 isn't allowed"},
+			},
+		},
+		{
+			name:    "temperature time",
+			data:    `TAF EGLL 211658Z 2118/2224 22008KT 9999 FEW040 TX25/2306Z`,
+			ruleset: Annex3,
+			expected: []Violation{
+				{Rule: RuleTemperatureTime, Severity: SeverityError, Message: "High temperature time is outside the validity period"},
+			},
+		},
+		{
+			name: "visibility",
+			data: `TAF EGLL 211658Z 2118/2224 22008KT 0820 FEW040
+  TEMPO 2202/2206 3SM BR`,
+			ruleset: Annex3,
+			expected: []Violation{
+				{Rule: RuleVisibilityValue, Severity: SeverityError, Message: "820 Meters isn't a reportable visibility"},
+				{Rule: RuleVisibilityValue, Severity: SeverityError, Location: "changes[0]", Message: "4828.03 Meters isn't a reportable visibility"},
+			},
+		},
+		{
+			name: "fmh1",
+			data: `TAF KJFK 211720Z 2118/2224 22008KT P6SM FEW040
+  TEMPO 2120/2124 2 1/2SM BR
+  PROB40 2122/2202 1SM TSRA`,
+			ruleset: FMH1,
+			expected: []Violation{
+				{Rule: RuleVisibilityValue, Severity: SeverityError, Location: "changes[0]", Message: "2.5 Miles isn't a reportable visibility"},
+				{Rule: RuleProbabilityValue, Severity: SeverityError, Location: "probabilities[0]", Message: "PROB40 isn't allowed"},
+				{Rule: RuleProbabilityLead, Severity: SeverityError, Location: "probabilities[0]", Message: "PROB group starts within 9h of the validity period"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fc, err := DecodeWithOptions(strings.NewReader(tc.data), Options{
+				Month: time.August,
+				Year:  2023,
+			})
+			if err != nil {
+				t.Fatalf("Error during parsing: %s", err)
+			}
+
+			if diff := deep.Equal(Validate(fc, tc.ruleset), tc.expected); diff != nil {
+				t.Error(diff)
+			}
+		})
+	}
+}